@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"turbotilt/internal/certs"
+	"turbotilt/internal/config"
+	"turbotilt/internal/render"
+)
+
+var certsForce bool
+
+var certsCmd = &cobra.Command{
+	Use:   "certs",
+	Short: "Create the development CA and the HTTPS certificates of the services",
+	Long: `Create a local certificate authority, shared by all your projects, and issue with it
+the certificates of the services of the manifest in the certs folder of the output directory.
+With 'tls: true' in turbotilt.yaml, they are mounted into the gateway, or into the application
+containers whose HTTPS is then configured (Spring Boot, Quarkus, Micronaut).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		configPath, isManifest, _ := config.FindConfiguration()
+		if !isManifest {
+			fmt.Println("❌ No manifest found. Use 'turbotilt init --generate-manifest' to create one.")
+			return
+		}
+
+		manifest, err := config.LoadManifest(configPath)
+		if err != nil {
+			fmt.Printf("❌ Error loading manifest: %v\n", err)
+			return
+		}
+
+		serviceList, _ := manifestServiceList(manifest, outputDir())
+		if err := issueCertificates(serviceList, certsForce); err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			return
+		}
+
+		if !manifest.TLS {
+			fmt.Printf("ℹ️ Add 'tls: true' to %s and run 'turbotilt init' to serve the services over HTTPS\n", configPath)
+		}
+	},
+}
+
+// issueCertificates issues the missing certificates of the services, or all of them with force,
+// creating the development CA on first use
+func issueCertificates(serviceList render.ServiceList, force bool) error {
+	caDir := certs.UserCADir()
+	if caDir == "" {
+		return fmt.Errorf("unable to locate the home directory of the user")
+	}
+
+	authority, created, err := certs.LoadOrCreateAuthority(caDir)
+	if err != nil {
+		return fmt.Errorf("error loading the development CA: %w", err)
+	}
+	caFile := filepath.Join(caDir, certs.CAFile)
+	if created {
+		fmt.Printf("🔐 Development CA created: %s\n", caFile)
+		printTrustInstructions(caFile)
+	}
+
+	dir := filepath.Join(serviceList.OutputDir, render.CertificatesDir)
+	hosts := render.CertificateHosts(serviceList)
+	for _, name := range sortedNames(hosts) {
+		if !force && certs.Exists(dir, name) {
+			if certs.Matches(dir, name, hosts[name]) {
+				continue
+			}
+			fmt.Printf("ℹ️ The hostnames of %s changed, reissuing its certificate\n", name)
+		}
+		files, err := authority.Issue(dir, name, hosts[name])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✅ Certificate of %s issued: %s\n", name, files.Certificate)
+	}
+	return nil
+}
+
+// printTrustInstructions explains how to trust the development CA on the current system
+func printTrustInstructions(caFile string) {
+	fmt.Println("▶️ Trust it once so that browsers accept the certificates of the services:")
+	switch runtime.GOOS {
+	case "darwin":
+		fmt.Printf("   sudo security add-trusted-cert -d -r trustRoot -k /Library/Keychains/System.keychain %s\n", caFile)
+	case "windows":
+		fmt.Printf("   certutil -addstore -f ROOT %s\n", caFile)
+	default:
+		fmt.Printf("   sudo cp %s /usr/local/share/ca-certificates/turbotilt-ca.crt && sudo update-ca-certificates\n", caFile)
+		fmt.Println("   (Firefox and Chrome on Linux use their own store: import it in the browser settings)")
+	}
+}
+
+// sortedNames returns the keys of a map in alphabetical order
+func sortedNames(m map[string][]string) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	rootCmd.AddCommand(certsCmd)
+
+	certsCmd.Flags().BoolVar(&certsForce, "force", false, "Issue again the certificates that already exist")
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"turbotilt/internal/config"
+	"turbotilt/internal/render"
+	"turbotilt/internal/runtime"
+)
+
+var chaosJitter time.Duration
+
+var chaosCmd = &cobra.Command{
+	Use:   "chaos",
+	Short: "Inject faults between the services and their dependencies",
+	Long: `Inject faults in the connections of the application services to the dependent
+services declared in the chaos section of the manifest, through the Toxiproxy API.
+The services are named by type or alias (postgresql for postgres...). The environment must
+be started.
+
+Examples:
+  turbotilt chaos latency postgres 500ms
+  turbotilt chaos cut redis
+  turbotilt chaos restore`,
+}
+
+var chaosLatencyCmd = &cobra.Command{
+	Use:   "latency <service> <duration>",
+	Short: "Delay the responses of a dependent service",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		latency, err := time.ParseDuration(args[1])
+		if err != nil {
+			fmt.Printf("❌ Error: invalid duration '%s' (e.g. 500ms, 2s)\n", args[1])
+			return
+		}
+		service := render.CanonicalServiceType(args[0])
+		runChaos(func(client *runtime.ChaosClient) error {
+			return client.Latency(service, latency, chaosJitter)
+		}, fmt.Sprintf("🐢 %s answers %s later", service, latency))
+	},
+}
+
+var chaosCutCmd = &cobra.Command{
+	Use:   "cut <service>",
+	Short: "Cut the connections to a dependent service",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		service := render.CanonicalServiceType(args[0])
+		runChaos(func(client *runtime.ChaosClient) error {
+			return client.Cut(service)
+		}, fmt.Sprintf("✂️ %s is unreachable, restore it with: turbotilt chaos restore %s", service, service))
+	},
+}
+
+var chaosRestoreCmd = &cobra.Command{
+	Use:   "restore [service]",
+	Short: "Remove the faults of a dependent service, or of all of them",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			runChaos(func(client *runtime.ChaosClient) error {
+				return client.Reset()
+			}, "✅ All the faults are removed")
+			return
+		}
+		service := render.CanonicalServiceType(args[0])
+		runChaos(func(client *runtime.ChaosClient) error {
+			return client.Restore(service)
+		}, fmt.Sprintf("✅ The faults of %s are removed", service))
+	},
+}
+
+var chaosStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "List the proxied services and their faults",
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := chaosClient()
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			return
+		}
+		proxies, err := client.Proxies()
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			return
+		}
+
+		fmt.Println("💥 Proxied services:")
+		for _, proxy := range proxies {
+			state := "ok"
+			if !proxy.Enabled {
+				state = "cut"
+			}
+			for _, toxic := range proxy.Toxics {
+				state += fmt.Sprintf(", %s %v", toxic.Type, toxic.Attributes)
+			}
+			fmt.Printf("   - %-16s %-24s %s\n", proxy.Name, proxy.Upstream, state)
+		}
+	},
+}
+
+// chaosClient returns the client of the Toxiproxy API of the environment declared in the manifest
+func chaosClient() (*runtime.ChaosClient, error) {
+	configPath, isManifest, err := config.FindConfiguration()
+	if err != nil || !isManifest {
+		return nil, fmt.Errorf("no manifest found, declare the proxied services in the chaos section of %s", config.ManifestFileName)
+	}
+	manifest, err := config.LoadManifest(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("error loading manifest: %w", err)
+	}
+	options := manifest.ChaosOptions()
+	if !options.Enabled {
+		return nil, fmt.Errorf("chaos is not enabled, declare the proxied services in the chaos section of %s", configPath)
+	}
+	return runtime.NewChaosClient(render.ChaosURL(options)), nil
+}
+
+// runChaos runs an action on the Toxiproxy API and reports its result
+func runChaos(action func(client *runtime.ChaosClient) error, success string) {
+	client, err := chaosClient()
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		return
+	}
+	if err := action(client); err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		return
+	}
+	fmt.Println(success)
+}
+
+func init() {
+	rootCmd.AddCommand(chaosCmd)
+	chaosCmd.AddCommand(chaosLatencyCmd)
+	chaosCmd.AddCommand(chaosCutCmd)
+	chaosCmd.AddCommand(chaosRestoreCmd)
+	chaosCmd.AddCommand(chaosStatusCmd)
+
+	chaosLatencyCmd.Flags().DurationVar(&chaosJitter, "jitter", 0, "Random variation of the latency (e.g. 100ms)")
+}
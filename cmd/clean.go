@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"turbotilt/internal/config"
+	"turbotilt/internal/render"
+)
+
+var cleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "Remove the files generated by Turbotilt",
+	Long: `Remove the Dockerfile, docker-compose.yml and Tiltfile generated by Turbotilt
+in the output directory (.turbotilt by default).
+Only files that still carry the Turbotilt header and were not edited since their
+generation are removed; hand-written or modified files are kept.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("🧹 Cleaning generated files...")
+		cleanGeneratedFiles()
+	},
+}
+
+// generatedFileCandidates returns the files Turbotilt may have generated for the current project,
+// in the output directory and, for projects initialized before it existed, in the project itself
+func generatedFileCandidates() []string {
+	dir := outputDir()
+	candidates := []string{
+		filepath.Join(dir, "Dockerfile"),
+		filepath.Join(dir, "docker-compose.yml"),
+		filepath.Join(dir, "Tiltfile"),
+	}
+	if filepath.Clean(dir) != "." {
+		candidates = append(candidates, "Dockerfile", "docker-compose.yml", "Tiltfile")
+	}
+	candidates = append(candidates, filepath.Join(dir, render.GatewayTLSFile))
+	for _, file := range append(append(render.ObservabilityFiles(), render.ToolFiles()...), render.ChaosFiles()...) {
+		candidates = append(candidates, filepath.Join(dir, filepath.FromSlash(file)))
+	}
+
+	// Dockerfiles of the services declared in the manifest, or of the detected application
+	names := []string{}
+	if configPath, isManifest, err := config.FindConfiguration(); err == nil && isManifest {
+		if manifest, err := config.LoadManifest(configPath); err == nil {
+			for _, service := range manifest.Services {
+				if service.Runtime == "" {
+					continue
+				}
+				names = append(names, service.Name)
+				if service.Path != "" && filepath.Clean(service.Path) != "." {
+					candidates = append(candidates, filepath.Join(service.Path, "Dockerfile"))
+				}
+			}
+		}
+	}
+	if cwd, err := os.Getwd(); err == nil {
+		names = append(names, filepath.Base(cwd))
+	}
+	if filepath.Clean(dir) != "." {
+		for _, name := range names {
+			candidates = append(candidates, filepath.Join(dir, name, "Dockerfile"))
+		}
+	}
+
+	return candidates
+}
+
+// cleanGeneratedFiles removes the unmodified generated files and reports the kept ones
+func cleanGeneratedFiles() {
+	result, err := render.CleanGeneratedFiles(generatedFileCandidates())
+	for _, file := range result.Removed {
+		fmt.Printf("   🗑️ %s\n", file)
+	}
+	for file, ownership := range result.Kept {
+		fmt.Printf("   ⚠️ %s kept (%s)\n", file, ownership)
+	}
+	if err != nil {
+		fmt.Printf("❌ Error cleaning files: %v\n", err)
+		return
+	}
+
+	// The fingerprint only describes the generated files
+	if err := os.Remove(filepath.Join(outputDir(), render.FingerprintFile)); err != nil && !os.IsNotExist(err) {
+		fmt.Printf("⚠️ Unable to remove the generation fingerprint: %v\n", err)
+	}
+
+	if len(result.Removed) == 0 && len(result.Kept) == 0 {
+		fmt.Println("ℹ️ No generated files found")
+		return
+	}
+	fmt.Printf("✅ %d file(s) removed.\n", len(result.Removed))
+}
+
+func init() {
+	rootCmd.AddCommand(cleanCmd)
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"turbotilt/internal/config"
+	"turbotilt/internal/render"
+)
+
+// regenerationFlags are the init flags replayed when the generated files are regenerated. --force is
+// not: a regeneration refuses to overwrite the files edited since the generation.
+var regenerationFlags = map[string]bool{
+	"framework":     true,
+	"port":          true,
+	"jdk":           true,
+	"dev":           true,
+	"services":      true,
+	"from-manifest": true,
+	"debug-port":    true,
+}
+
+// recordFingerprint records the inputs of the generation and the init flags used
+func recordFingerprint(cmd *cobra.Command, dir string) error {
+	var args []string
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		if regenerationFlags[flag.Name] {
+			args = append(args, fmt.Sprintf("--%s=%s", flag.Name, flag.Value.String()))
+		}
+	})
+	return render.WriteFingerprint(dir, render.NewFingerprint(config.GenerationInputs(), args))
+}
+
+// staleInputs returns the inputs changed since the files of dir were generated,
+// and the fingerprint recorded then. Files generated without a fingerprint are never stale.
+func staleInputs(dir string) ([]string, render.Fingerprint) {
+	recorded, err := render.ReadFingerprint(dir)
+	if err != nil {
+		return nil, recorded
+	}
+	return recorded.Changes(render.NewFingerprint(config.GenerationInputs(), nil)), recorded
+}
+
+// regenerateFiles runs init again with the flags recorded in the fingerprint, except --force
+// recorded by the previous releases
+func regenerateFiles(dir string, recorded render.Fingerprint) error {
+	args := []string{"init", "--out-dir", dir, "--no-update"}
+	for _, arg := range recorded.InitArgs {
+		if !strings.HasPrefix(arg, "--force") {
+			args = append(args, arg)
+		}
+	}
+	initProcess := exec.Command(os.Args[0], args...)
+	initProcess.Stdout = os.Stdout
+	initProcess.Stderr = os.Stderr
+	if err := initProcess.Run(); err != nil {
+		return fmt.Errorf("the generation failed (%v), files edited since the generation are only overwritten by 'turbotilt init --force'", err)
+	}
+	return nil
+}
+
+// ensureFreshFiles regenerates the files of dir when their inputs changed since the generation,
+// or only warns when regenerate is false
+func ensureFreshFiles(dir string, regenerate bool) error {
+	changes, recorded := staleInputs(dir)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	fmt.Println("⚠️ Generated files are stale, changed since the last generation:")
+	for _, change := range changes {
+		fmt.Printf("   - %s\n", filepath.FromSlash(change))
+	}
+
+	if !regenerate {
+		fmt.Println("ℹ️ Starting with the existing files, run 'turbotilt init' to regenerate them")
+		return nil
+	}
+
+	fmt.Println("🔄 Regenerating files...")
+	return regenerateFiles(dir, recorded)
+}
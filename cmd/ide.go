@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"turbotilt/internal/config"
+	"turbotilt/internal/render"
+)
+
+var (
+	ideVSCode   bool
+	ideIntelliJ bool
+	ideForce    bool
+)
+
+var ideCmd = &cobra.Command{
+	Use:   "ide",
+	Short: "Generate IDE remote debugging configurations",
+	Long: `Generate the remote-attach configurations of the services declared with 'debug: true'
+in turbotilt.yaml, using the debug ports allocated by Turbotilt:
+.vscode/launch.json for VS Code (--vscode) and .run/*.run.xml for IntelliJ IDEA (--intellij).
+Without flag, both are generated.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		configPath, isManifest, _ := config.FindConfiguration()
+		if !isManifest {
+			fmt.Println("❌ No manifest found. Remote debugging is enabled per service with 'debug: true' in turbotilt.yaml.")
+			return
+		}
+
+		manifest, err := config.LoadManifest(configPath)
+		if err != nil {
+			fmt.Printf("❌ Error loading manifest: %v\n", err)
+			return
+		}
+
+		serviceList, warnings := manifestServiceList(manifest, outputDir())
+		for _, warning := range warnings {
+			fmt.Printf("⚠️ Warning: %v\n", warning)
+		}
+
+		debugged := 0
+		for _, opts := range serviceList.Services {
+			if opts.Debug {
+				debugged++
+				fmt.Printf("🐞 %s: debug port %s\n", opts.ServiceName, opts.DebugPort)
+			}
+		}
+		if debugged == 0 {
+			fmt.Printf("ℹ️ No service of %s has 'debug: true'\n", configPath)
+			return
+		}
+
+		if !ideVSCode && !ideIntelliJ {
+			ideVSCode, ideIntelliJ = true, true
+		}
+
+		if ideVSCode {
+			path, err := render.GenerateVSCodeLaunch(serviceList.Services, ideForce)
+			if err != nil {
+				fmt.Printf("❌ Error generating the VS Code configurations: %v\n", err)
+				return
+			}
+			fmt.Printf("✅ VS Code configurations written to %s\n", path)
+		}
+
+		if ideIntelliJ {
+			paths, err := render.GenerateIntelliJRunConfigs(serviceList.Services, ideForce)
+			if err != nil {
+				fmt.Printf("❌ Error generating the IntelliJ IDEA configurations: %v\n", err)
+				return
+			}
+			for _, path := range paths {
+				fmt.Printf("✅ IntelliJ IDEA configuration written to %s\n", path)
+			}
+		}
+
+		fmt.Println("▶️ Start the environment (turbotilt up), then attach the debugger from the IDE")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(ideCmd)
+
+	ideCmd.Flags().BoolVar(&ideVSCode, "vscode", false, "Generate .vscode/launch.json")
+	ideCmd.Flags().BoolVar(&ideIntelliJ, "intellij", false, "Generate IntelliJ IDEA run configurations in .run")
+	ideCmd.Flags().BoolVar(&ideForce, "force", false, "Overwrite configurations not generated by Turbotilt")
+}
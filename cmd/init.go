@@ -1,9 +1,11 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -22,6 +24,9 @@ var (
 	detectServices   bool
 	generateManifest bool
 	fromManifest     bool
+	forceOverwrite   bool
+	showDiff         bool
+	initDebugPort    string
 )
 
 var initCmd = &cobra.Command{
@@ -29,6 +34,8 @@ var initCmd = &cobra.Command{
 	Short: "Scan and generate Tiltfile & Compose",
 	Run: func(cmd *cobra.Command, args []string) {
 		fmt.Println("🔍 Initializing Turbotilt...")
+		defer startPreview()()
+		dir := outputDir()
 
 		// Look for an existing manifest
 		configPath, isManifest, _ := config.FindConfiguration()
@@ -50,46 +57,73 @@ var initCmd = &cobra.Command{
 			fmt.Printf("✅ Manifest loaded with %d service(s)\n", len(manifest.Services))
 
 			// Convert manifest services to render options
-			serviceList := render.ServiceList{
-				Services: []render.Options{},
-			}
-
-			for _, service := range manifest.Services {
-				// Ignore dependent services (without runtime)
-				if service.Runtime == "" {
-					continue
-				}
-
-				opts, err := config.ConvertManifestToRenderOptions(service)
-				if err != nil {
-					fmt.Printf("⚠️ Warning: %v\n", err)
-					continue
-				}
-
-				serviceList.Services = append(serviceList.Services, *opts)
+			serviceList, warnings := manifestServiceList(manifest, dir)
+			for _, warning := range warnings {
+				fmt.Printf("⚠️ Warning: %v\n", warning)
 			}
 
 			// Generate files for a multi-service project
 			if len(serviceList.Services) > 0 {
 				fmt.Println("🔧 Generating configurations for a multi-service project...")
 
+				for _, opts := range serviceList.Services {
+					if !render.UsesGeneratedDockerfile(opts) {
+						continue
+					}
+					if err := render.GenerateDockerfile(opts); err != nil {
+						generationFailed("Dockerfile for "+opts.ServiceName, err)
+					}
+				}
+
+				// Certificates are files of the environment, not previewed
+				if serviceList.TLS.Enabled && !dryRun && !showDiff {
+					if err := issueCertificates(serviceList, false); err != nil {
+						fmt.Printf("❌ Error issuing the HTTPS certificates: %v\n", err)
+						return
+					}
+				}
+
 				if err := render.GenerateMultiServiceCompose(serviceList); err != nil {
-					fmt.Printf("❌ Error generating docker-compose.yml: %v\n", err)
-					return
+					generationFailed("docker-compose.yml", err)
 				}
 
 				if err := render.GenerateMultiServiceTiltfile(serviceList); err != nil {
-					fmt.Printf("❌ Error generating Tiltfile: %v\n", err)
-					return
+					generationFailed("Tiltfile", err)
+				}
+
+				if err := recordFingerprint(cmd, dir); err != nil {
+					fmt.Printf("⚠️ Warning: unable to record the generation fingerprint: %v\n", err)
 				}
 
 				fmt.Println("✨ Turbotilt configuration completed!")
-				fmt.Println("📋 Files generated from manifest:")
-				fmt.Println("   - docker-compose.yml")
-				fmt.Println("   - Tiltfile")
+				fmt.Printf("📋 Files generated from manifest in %s:\n", dir)
+				for _, opts := range serviceList.Services {
+					if render.UsesGeneratedDockerfile(opts) {
+						fmt.Printf("   - %s\n", render.DockerfilePath(opts))
+					}
+				}
+				fmt.Printf("   - %s\n", filepath.Join(dir, "docker-compose.yml"))
+				fmt.Printf("   - %s\n", filepath.Join(dir, "Tiltfile"))
+				if serviceList.Observability.Enabled {
+					for _, file := range render.ObservabilityFiles() {
+						fmt.Printf("   - %s\n", filepath.Join(dir, filepath.FromSlash(file)))
+					}
+				}
+				for _, endpoint := range render.Endpoints(serviceList) {
+					if endpoint.Kind == "tool" {
+						fmt.Printf("🧰 %s: %s\n", endpoint.Name, endpoint.URL)
+					}
+				}
 				fmt.Println("\n▶️ To start the environment: turbotilt up")
 				return
 			}
+
+			// The detected application is generated without the environment options of the manifest
+			if sections := manifest.EnvironmentSections(); len(sections) > 0 {
+				fmt.Printf("❌ Error: '%s' in %s apply to the application services, declare the application with a 'runtime'\n",
+					strings.Join(sections, "', '"), configPath)
+				return
+			}
 		}
 
 		// If we get here, proceed with auto-detection or CLI options
@@ -126,27 +160,32 @@ var initCmd = &cobra.Command{
 			} else {
 				fmt.Println("ℹ️ No dependent services detected")
 			}
+
+			// The application runs its migrations, the seed section of the manifest runs them beforehand
+			if migrations, ok := scan.DetectMigrations("."); ok {
+				fmt.Printf("ℹ️ %s migrations detected in %s (see 'seed' in %s to run them before the application)\n",
+					migrations.Tool, migrations.Dir, config.ManifestFileName)
+			}
 		}
 
-		// Determine application name (current folder by default)
-		appName := "app"
-		cwd, err := os.Getwd()
-		if err == nil {
-			appName = filepath.Base(cwd)
+		// Third-party APIs can be replaced with WireMock stubs from the manifest
+		externalURLs := scan.DetectExternalURLs(".")
+		for _, url := range externalURLs {
+			fmt.Printf("ℹ️ External API %s detected in %s (see 'stubs' in %s to replace it with WireMock)\n",
+				url.Host, url.Property, config.ManifestFileName)
 		}
 
-		// Prepare render options
-		renderOpts := render.Options{
-			ServiceName: appName, // Use the name to identify it in a multi-service context
-			Framework:   framework,
-			AppName:     appName,
-			Port:        port,
-			JDKVersion:  jdkVersion,
-			DevMode:     devMode,
-			Path:        ".",
-			Services:    services,
+		if initDebugPort != "" {
+			if _, err := strconv.Atoi(initDebugPort); err != nil {
+				fmt.Printf("❌ Error: --debug-port '%s' is not a port number\n", initDebugPort)
+				return
+			}
 		}
 
+		// Prepare render options
+		renderOpts := appRenderOptions(framework, services, dir)
+		appName := renderOpts.AppName
+
 		// Generate manifest if requested
 		if generateManifest {
 			fmt.Println("📝 Generating turbotilt.yaml manifest...")
@@ -188,44 +227,70 @@ var initCmd = &cobra.Command{
 
 			// Generate manifest from configuration
 			manifest := config.GenerateManifestFromConfig(cfg)
+			if renderOpts.Debug {
+				manifest.Services[0].Debug = true
+				manifest.Services[0].DebugPort = renderOpts.DebugPort
+			}
+
+			// Offer the WireMock stubs of the detected third-party APIs, the hosts to stub are opted into
+			var stubHosts []string
+			seen := make(map[string]bool)
+			for _, url := range externalURLs {
+				if !seen[url.Host] {
+					seen[url.Host] = true
+					stubHosts = append(stubHosts, url.Host)
+				}
+			}
 
 			// Save manifest
-			if err := config.SaveManifest(manifest, config.ManifestFileName); err != nil {
+			if err := config.SaveManifestSuggestingStubs(manifest, config.ManifestFileName, stubHosts); err != nil {
 				fmt.Printf("❌ Error saving manifest: %v\n", err)
 			} else {
 				fmt.Printf("✅ Manifest %s generated successfully!\n", config.ManifestFileName)
 			}
 		}
 
+		// Report how the application image is built (existing Dockerfile, Jib, Buildpacks)
+		switch renderOpts.BuildStrategy {
+		case scan.BuildExistingDockerfile:
+			fmt.Println("ℹ️ Existing Dockerfile found, it will be reused")
+		case scan.BuildJib:
+			fmt.Println("ℹ️ Jib plugin detected, the image will be built with Jib")
+		case scan.BuildBuildpacks:
+			fmt.Println("ℹ️ Buildpacks configuration detected, the image will be built with Spring Boot")
+		}
+
 		// Generate files
 		if err := render.GenerateDockerfile(renderOpts); err != nil {
-			fmt.Printf("❌ Error generating Dockerfile: %v\n", err)
-			return
+			generationFailed("Dockerfile", err)
 		}
 
 		// Use the new docker-compose generator with service support
 		if len(services) > 0 {
 			if err := render.GenerateComposeWithServices(renderOpts); err != nil {
-				fmt.Printf("❌ Error generating docker-compose.yml: %v\n", err)
-				return
+				generationFailed("docker-compose.yml", err)
 			}
 		} else {
 			if err := render.GenerateCompose(renderOpts); err != nil {
-				fmt.Printf("❌ Error generating docker-compose.yml: %v\n", err)
-				return
+				generationFailed("docker-compose.yml", err)
 			}
 		}
 
 		if err := render.GenerateTiltfile(renderOpts); err != nil {
-			fmt.Printf("❌ Error generating Tiltfile: %v\n", err)
-			return
+			generationFailed("Tiltfile", err)
+		}
+
+		if err := recordFingerprint(cmd, dir); err != nil {
+			fmt.Printf("⚠️ Warning: unable to record the generation fingerprint: %v\n", err)
 		}
 
 		fmt.Println("✨ Turbotilt configuration completed!")
 		fmt.Println("📋 Generated files:")
-		fmt.Println("   - Dockerfile")
-		fmt.Println("   - docker-compose.yml")
-		fmt.Println("   - Tiltfile")
+		if render.UsesGeneratedDockerfile(renderOpts) {
+			fmt.Printf("   - %s\n", render.DockerfilePath(renderOpts))
+		}
+		fmt.Printf("   - %s\n", filepath.Join(dir, "docker-compose.yml"))
+		fmt.Printf("   - %s\n", filepath.Join(dir, "Tiltfile"))
 		if generateManifest {
 			fmt.Printf("   - %s\n", config.ManifestFileName)
 		}
@@ -233,6 +298,86 @@ var initCmd = &cobra.Command{
 	},
 }
 
+// manifestServiceList converts the application services of the manifest to render options.
+// Services that cannot be converted are returned as warnings.
+func manifestServiceList(manifest config.Manifest, dir string) (render.ServiceList, []error) {
+	serviceList := render.ServiceList{
+		Services:      []render.Options{},
+		Force:         forceOverwrite,
+		OutputDir:     dir,
+		Observability: manifest.ObservabilityOptions(),
+		Gateway:       manifest.GatewayOptions(),
+		TLS:           manifest.TLSOptions(),
+		Tools:         manifest.Tools,
+		Seed:          manifest.SeedOptions(),
+		Chaos:         manifest.ChaosOptions(),
+		Stubs:         manifest.StubOptions(),
+	}
+	dependencies := manifest.ApplicationDependencies()
+
+	var warnings []error
+	for _, service := range manifest.Services {
+		// Ignore dependent services (without runtime)
+		if service.Runtime == "" {
+			continue
+		}
+
+		opts, err := config.ConvertManifestToRenderOptions(service)
+		if err != nil {
+			warnings = append(warnings, err)
+			continue
+		}
+		opts.Services = dependencies[service.Name]
+		opts.Force = forceOverwrite
+		opts.OutputDir = dir
+
+		serviceList.Services = append(serviceList.Services, *opts)
+	}
+	render.AssignDebugPorts(serviceList.Services)
+
+	return serviceList, warnings
+}
+
+// appRenderOptions returns the render options of the application in the current directory,
+// from the init flags and the detected framework and dependent services
+func appRenderOptions(framework string, services []scan.ServiceConfig, dir string) render.Options {
+	// Determine application name (current folder by default)
+	appName := "app"
+	if cwd, err := os.Getwd(); err == nil {
+		appName = filepath.Base(cwd)
+	}
+
+	opts := render.Options{
+		ServiceName: appName, // Use the name to identify it in a multi-service context
+		Framework:   framework,
+		AppName:     appName,
+		Port:        port,
+		JDKVersion:  jdkVersion,
+		DevMode:     devMode,
+		Path:        ".",
+		Services:    services,
+		Force:       forceOverwrite,
+		OutputDir:   dir,
+		Debug:       initDebugPort != "",
+		DebugPort:   initDebugPort,
+	}
+
+	// Detect how the application image is built (existing Dockerfile, Jib, Buildpacks)
+	opts.BuildStrategy = render.ResolveBuildStrategy(opts)
+	return opts
+}
+
+// generationFailed reports an error of the generation and exits with an error status, so that the
+// regeneration run by 'turbotilt up' fails instead of starting with stale files
+func generationFailed(file string, err error) {
+	fmt.Printf("❌ Error generating %s: %v\n", file, err)
+	var overwrite *render.OverwriteError
+	if errors.As(err, &overwrite) {
+		fmt.Printf("ℹ️ Run 'turbotilt init --force' to overwrite %s\n", overwrite.Path)
+	}
+	os.Exit(1)
+}
+
 func init() {
 	rootCmd.AddCommand(initCmd)
 
@@ -244,4 +389,7 @@ func init() {
 	initCmd.Flags().BoolVarP(&detectServices, "services", "s", true, "Detect and configure dependent services (MySQL, PostgreSQL, etc.)")
 	initCmd.Flags().BoolVarP(&generateManifest, "generate-manifest", "g", false, "Generate a turbotilt.yaml manifest from detection")
 	initCmd.Flags().BoolVarP(&fromManifest, "from-manifest", "m", false, "Initialize project from an existing manifest")
+	initCmd.Flags().BoolVar(&showDiff, "diff", false, "Show the changes to the files on disk instead of writing them")
+	initCmd.Flags().StringVar(&initDebugPort, "debug-port", "", "Start the JVM with the JDWP agent, published on this host port (e.g. 5005)")
+	initCmd.Flags().BoolVar(&forceOverwrite, "force", false, "Overwrite files not generated by Turbotilt or modified since their generation")
 }
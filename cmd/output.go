@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+
+	"turbotilt/internal/config"
+)
+
+// outputDir returns the directory receiving the generated files
+func outputDir() string {
+	return config.ResolveOutputDir(outDir)
+}
+
+// runOutputDir returns the directory containing the files to run. Projects initialized
+// before the output directory existed keep their files in the current directory.
+func runOutputDir() string {
+	dir := outputDir()
+	if fileExists(filepath.Join(dir, "docker-compose.yml")) {
+		return dir
+	}
+	if fileExists("docker-compose.yml") {
+		return "."
+	}
+	return dir
+}
+
+// fileExists indicates if a regular file exists at path
+func fileExists(path string) bool {
+	stat, err := os.Stat(path)
+	return err == nil && !stat.IsDir()
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"turbotilt/internal/render"
+)
+
+// startPreview renders the generated files in memory when --dry-run or --diff is set.
+// The returned function restores the filesystem and prints the files or their diff.
+func startPreview() func() {
+	if !dryRun && !showDiff {
+		return func() {}
+	}
+
+	mem := render.NewMemoryFileSystem()
+	previous := render.SetFileSystem(mem)
+	return func() {
+		render.SetFileSystem(previous)
+		if showDiff {
+			printDiff(mem)
+		} else {
+			printFiles(mem)
+		}
+	}
+}
+
+// printFiles prints the content of the files rendered in memory
+func printFiles(mem *render.MemoryFileSystem) {
+	fmt.Println("\n🔍 [DRY-RUN] No file was written, files that would be generated:")
+	for _, file := range mem.Files() {
+		content, _ := mem.Content(file)
+		fmt.Printf("\n==> %s <==\n%s", file, content)
+		printRequiresForce(mem, file)
+	}
+}
+
+// printDiff prints the unified diff between the files on disk and the files rendered in memory
+func printDiff(mem *render.MemoryFileSystem) {
+	fmt.Println("\n🔍 No file was written, changes that would be applied:")
+
+	changed := 0
+	for _, file := range mem.Files() {
+		content, _ := mem.Content(file)
+
+		oldName := file
+		current, err := os.ReadFile(file)
+		if os.IsNotExist(err) {
+			oldName = "/dev/null"
+		} else if err != nil {
+			fmt.Printf("⚠️ Unable to read %s: %v\n", file, err)
+			continue
+		}
+
+		if diff := render.UnifiedDiff(oldName, file, current, content); diff != "" {
+			fmt.Print("\n" + diff)
+			printRequiresForce(mem, file)
+			changed++
+		}
+	}
+
+	if changed == 0 {
+		fmt.Println("✅ Files are up to date")
+	}
+}
+
+// printRequiresForce notes that a file rendered in memory would only be written with --force
+func printRequiresForce(mem *render.MemoryFileSystem, file string) {
+	if ownership, ok := mem.RequiresForce(file); ok {
+		fmt.Printf("⚠️ %s is %s, it would only be overwritten with --force\n", file, ownership)
+	}
+}
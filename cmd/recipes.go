@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"turbotilt/internal/render"
+)
+
+var ejectRecipeForce bool
+
+var recipesCmd = &cobra.Command{
+	Use:   "recipes",
+	Short: "Manage the recipes of the dependent services",
+	Long: `Manage the recipes describing the dependent services (databases, brokers...).
+Recipes are looked up in the recipes folder of the output directory (project,
+.turbotilt/recipes by default), then in
+~/.config/turbotilt/recipes (user), then among the recipes shipped with Turbotilt.
+A recipe overrides the one of the same name with a lower priority.`,
+}
+
+var listRecipesCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the recipes and where the one in use comes from",
+	Run: func(cmd *cobra.Command, args []string) {
+		catalog, err := render.LoadRecipes()
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			return
+		}
+
+		fmt.Println("📋 Recipes:")
+		for _, recipe := range catalog.Recipes() {
+			image := recipe.Image
+			if recipe.Version != "" {
+				image += ":" + recipe.Version
+			}
+			fmt.Printf("   - %-16s %-52s %s\n", recipe.Name, image, recipe.Source)
+		}
+	},
+}
+
+var ejectRecipeCmd = &cobra.Command{
+	Use:   "eject <name>",
+	Short: "Copy a shipped recipe to the project recipes of the output directory to customize it",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		finishPreview := startPreview()
+		path, err := render.EjectRecipe(args[0], ejectRecipeForce)
+		finishPreview()
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			return
+		}
+		if dryRun {
+			return
+		}
+
+		fmt.Printf("✅ Recipe copied to %s\n", path)
+		fmt.Println("▶️ Edit it, then regenerate the files: turbotilt init")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(recipesCmd)
+	recipesCmd.AddCommand(listRecipesCmd)
+	recipesCmd.AddCommand(ejectRecipeCmd)
+
+	ejectRecipeCmd.Flags().BoolVar(&ejectRecipeForce, "force", false, "Overwrite an existing project recipe")
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"turbotilt/internal/runtime"
+)
+
+var (
+	resetData bool
+	resetYes  bool
+)
+
+var resetCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "Reset the state of the development environment",
+	Long: `Reset the state of the development environment.
+With --data, the environment is stopped and the named volumes of the dependent
+services (postgres_data, mysql_data...) are removed: the databases start empty,
+then run their initialization again. The volumes mounted by the applications or
+the tools are kept, as are the snapshots. The volumes removed are listed and
+confirmed first, unless --yes is given.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !resetData {
+			fmt.Println("ℹ️ Nothing to reset, use --data to wipe the data of the dependent services")
+			return
+		}
+
+		volumes, err := runtime.DependencyVolumes(runOutputDir())
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			return
+		}
+		if len(volumes) == 0 {
+			fmt.Println("ℹ️ The dependent services have no named volume to remove")
+			return
+		}
+		fmt.Println("🧨 Wiping the data of the environment:")
+		for _, volume := range volumes {
+			fmt.Printf("   🗑️ %s (%s)\n", volume.Name, strings.Join(volume.Services, ", "))
+		}
+		if !resetYes && !confirm("Remove these volumes?") {
+			fmt.Println("ℹ️ Reset cancelled, nothing was removed")
+			return
+		}
+		if err := runtime.ResetData(runOutputDir(), volumes); err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			return
+		}
+		fmt.Println("✅ Data wiped, start the environment again with: turbotilt up")
+	},
+}
+
+// confirm asks a yes/no question on the standard input, no being the answer by default
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+func init() {
+	rootCmd.AddCommand(resetCmd)
+	resetCmd.Flags().BoolVar(&resetData, "data", false, "Remove the volumes of the dependent services")
+	resetCmd.Flags().BoolVarP(&resetYes, "yes", "y", false, "Remove the volumes without asking for confirmation")
+}
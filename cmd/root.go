@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"turbotilt/internal/render"
 	"turbotilt/internal/update"
 
 	"github.com/spf13/cobra"
@@ -19,7 +20,8 @@ var (
 var (
 	dryRun    bool
 	debugMode bool
-	noUpdate  bool // Flag to disable update checks
+	noUpdate  bool   // Flag to disable update checks
+	outDir    string // Directory receiving the generated files
 )
 
 var rootCmd = &cobra.Command{
@@ -27,6 +29,11 @@ var rootCmd = &cobra.Command{
 	Short:   "Turbotilt CLI",
 	Long:    `Turbotilt - Generate and run cloud-native dev environments.`,
 	Version: Version,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		// The project templates and recipes are customized in the output directory
+		render.SetProjectTemplatesDir(outputDir())
+		render.SetProjectRecipesDir(outputDir())
+	},
 }
 
 func init() {
@@ -34,6 +41,7 @@ func init() {
 	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Simulate execution without making changes")
 	rootCmd.PersistentFlags().BoolVar(&debugMode, "debug", false, "Enable debug mode with verbose output")
 	rootCmd.PersistentFlags().BoolVar(&noUpdate, "no-update", false, "Disable automatic update checks")
+	rootCmd.PersistentFlags().StringVar(&outDir, "out-dir", "", "Directory of the generated files (default: manifest 'output' or .turbotilt)")
 
 	// Custom version template
 	rootCmd.SetVersionTemplate(`Turbotilt {{.Version}}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"turbotilt/internal/runtime"
+)
+
+var snapshotForce bool
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Save and restore the data of the dependent services",
+	Long: `Save and restore the named volumes of the dependent services (postgres_data,
+mysql_data...) to get back to a known data state.
+Snapshots are archives stored in .turbotilt/snapshots. The services using the
+volumes are stopped while they are archived or restored, then started again.`,
+}
+
+var saveSnapshotCmd = &cobra.Command{
+	Use:   "save <name>",
+	Short: "Archive the volumes of the dependent services in a snapshot",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Printf("💾 Saving snapshot %s...\n", args[0])
+		if err := runtime.SaveSnapshot(runOutputDir(), runtime.SnapshotsDir(outputDir()), args[0], snapshotForce); err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			return
+		}
+		fmt.Printf("✅ Snapshot %s saved, restore it with: turbotilt snapshot restore %s\n", args[0], args[0])
+	},
+}
+
+var restoreSnapshotCmd = &cobra.Command{
+	Use:   "restore <name>",
+	Short: "Replace the data of the volumes with a snapshot",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Printf("♻️ Restoring snapshot %s...\n", args[0])
+		if err := runtime.RestoreSnapshot(runOutputDir(), runtime.SnapshotsDir(outputDir()), args[0]); err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			return
+		}
+		fmt.Printf("✅ Snapshot %s restored\n", args[0])
+	},
+}
+
+var listSnapshotsCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the saved snapshots",
+	Run: func(cmd *cobra.Command, args []string) {
+		snapshots, err := runtime.ListSnapshots(runtime.SnapshotsDir(outputDir()))
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			return
+		}
+		if len(snapshots) == 0 {
+			fmt.Println("ℹ️ No snapshot, save one with: turbotilt snapshot save <name>")
+			return
+		}
+
+		fmt.Println("📋 Snapshots:")
+		for _, snapshot := range snapshots {
+			fmt.Printf("   - %-20s %s  %8.1f MB  %v\n", snapshot.Name, snapshot.Created.Format("2006-01-02 15:04"),
+				float64(snapshot.Size)/(1024*1024), snapshot.Volumes)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(snapshotCmd)
+	snapshotCmd.AddCommand(saveSnapshotCmd)
+	snapshotCmd.AddCommand(restoreSnapshotCmd)
+	snapshotCmd.AddCommand(listSnapshotsCmd)
+
+	saveSnapshotCmd.Flags().BoolVar(&snapshotForce, "force", false, "Replace an existing snapshot")
+}
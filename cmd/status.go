@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"turbotilt/internal/config"
+	"turbotilt/internal/render"
+	"turbotilt/internal/scan"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "List the URLs of the services of the environment",
+	Run: func(cmd *cobra.Command, args []string) {
+		serviceList, err := statusServiceList()
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			return
+		}
+
+		titles := map[string]string{
+			"app":           "🌐 Services",
+			"gateway":       "🚪 Gateway",
+			"route":         "🔀 Gateway routes",
+			"debug":         "🐞 Debug ports (JDWP)",
+			"observability": "📊 Observability",
+			"chaos":         "💥 Fault injection (Toxiproxy)",
+			"tool":          "🧰 Tools",
+		}
+
+		kind := ""
+		for _, endpoint := range render.Endpoints(serviceList) {
+			if endpoint.Kind != kind {
+				kind = endpoint.Kind
+				fmt.Printf("%s:\n", titles[kind])
+			}
+			fmt.Printf("   - %-20s %s\n", endpoint.Name, endpoint.URL)
+		}
+	},
+}
+
+// statusServiceList returns the services of the manifest, or the detected application
+func statusServiceList() (render.ServiceList, error) {
+	dir := outputDir()
+
+	if configPath, isManifest, _ := config.FindConfiguration(); isManifest {
+		manifest, err := config.LoadManifest(configPath)
+		if err != nil {
+			return render.ServiceList{}, fmt.Errorf("error loading manifest: %w", err)
+		}
+		serviceList, _ := manifestServiceList(manifest, dir)
+		return serviceList, nil
+	}
+
+	framework, err := scan.DetectFramework()
+	if err != nil {
+		return render.ServiceList{}, fmt.Errorf("error detecting framework: %w", err)
+	}
+	return render.ServiceList{
+		Services:  []render.Options{appRenderOptions(framework, nil, dir)},
+		OutputDir: dir,
+	}, nil
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+}
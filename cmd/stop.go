@@ -6,7 +6,7 @@ import (
 
 	"github.com/spf13/cobra"
 
-	"turbotilt/internal/logger"
+	"turbotilt/internal/runtime"
 )
 
 var stopCmd = &cobra.Command{
@@ -14,13 +14,14 @@ var stopCmd = &cobra.Command{
 	Short: "Stops the development environment",
 	Run: func(cmd *cobra.Command, args []string) {
 		fmt.Println("🛑 Stopping development environment...")
+		dir := runOutputDir()
 
 		// Check if Tilt is running
 		tiltRunning := exec.Command("powershell.exe", "-Command", "Get-Process | Where-Object { $_.ProcessName -eq 'tilt' }").Run() == nil
 
 		if tiltRunning {
 			fmt.Println("⏳ Stopping Tilt...")
-			stopTilt := exec.Command("tilt", "down")
+			stopTilt := exec.Command("tilt", append([]string{"down"}, runtime.TiltfileArgs(dir)...)...)
 			if err := stopTilt.Run(); err != nil {
 				fmt.Printf("❌ Error stopping Tilt: %v\n", err)
 			} else {
@@ -28,7 +29,8 @@ var stopCmd = &cobra.Command{
 			}
 		} else {
 			fmt.Println("⏳ Stopping Docker Compose...")
-			stopCompose := exec.Command("docker", "compose", "down")
+			args := append([]string{"compose"}, runtime.ComposeFileArgs(dir)...)
+			stopCompose := exec.Command("docker", append(args, "down")...)
 			if err := stopCompose.Run(); err != nil {
 				fmt.Printf("❌ Error stopping Docker Compose: %v\n", err)
 			} else {
@@ -39,16 +41,7 @@ var stopCmd = &cobra.Command{
 		// Offer to clean temporary files
 		if cleanupFlag {
 			fmt.Println("⏳ Cleaning temporary files...")
-			filesToClean := []string{"Dockerfile", "docker-compose.yml", "Tiltfile"}
-
-			for _, file := range filesToClean {
-				cleanFile := exec.Command("powershell.exe", "-Command", fmt.Sprintf("Remove-Item -Path '%s' -ErrorAction SilentlyContinue", file))
-				if err := cleanFile.Run(); err != nil {
-					logger.Debug("Failed to clean file %s: %v", file, err)
-				}
-			}
-
-			fmt.Println("✅ Temporary files cleaned.")
+			cleanGeneratedFiles()
 		}
 
 		fmt.Println("✨ Environment stopped.")
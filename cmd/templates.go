@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"turbotilt/internal/config"
+	"turbotilt/internal/render"
+	"turbotilt/internal/scan"
+)
+
+var ejectForce bool
+
+var templatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "Manage the templates of the generated files",
+	Long: `Manage the templates used to generate the Dockerfiles and the Tiltfile.
+Templates are looked up in the templates folder of the output directory (project,
+.turbotilt/templates by default), then in templates/ (deprecated), then in
+~/.config/turbotilt/templates (user), then among the templates shipped with Turbotilt.`,
+}
+
+var listTemplatesCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the templates and where the one in use comes from",
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("📋 Templates:")
+		for _, info := range render.ListTemplates() {
+			fmt.Printf("   - %-28s %s\n", info.Name, info.Source)
+		}
+	},
+}
+
+var ejectTemplateCmd = &cobra.Command{
+	Use:   "eject <name>",
+	Short: "Copy a shipped template to the project templates of the output directory to customize it",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		path, err := render.EjectTemplate(args[0], ejectForce)
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			return
+		}
+
+		fmt.Printf("✅ Template copied to %s\n", path)
+		fmt.Println("▶️ Edit it, then regenerate the files: turbotilt init")
+	},
+}
+
+var templateDataCmd = &cobra.Command{
+	Use:   "data [service]",
+	Short: "Print the data passed to the templates for a service",
+	Long: `Print, as JSON, the exact data passed to each template when generating the files
+of a service. Field names are the ones to use in the templates, e.g. {{.Port}}.
+Without a manifest, the data of the application in the current directory is printed.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := ""
+		if len(args) > 0 {
+			name = args[0]
+		}
+
+		data, err := templateData(name)
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			return
+		}
+
+		out, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			fmt.Printf("❌ Error encoding template data: %v\n", err)
+			return
+		}
+		fmt.Println(string(out))
+	},
+}
+
+// templateData returns the data rendered into the templates for the named service,
+// from the manifest when there is one or from detection otherwise
+func templateData(name string) (map[string]interface{}, error) {
+	dir := outputDir()
+
+	if configPath, isManifest, _ := config.FindConfiguration(); isManifest {
+		manifest, err := config.LoadManifest(configPath)
+		if err != nil {
+			return nil, fmt.Errorf("error loading manifest: %w", err)
+		}
+
+		serviceList, _ := manifestServiceList(manifest, dir)
+		for _, opts := range serviceList.Services {
+			if name == "" || opts.ServiceName == name {
+				return render.TemplateData(opts, &serviceList), nil
+			}
+		}
+		return nil, fmt.Errorf("no application service named '%s' in %s", name, configPath)
+	}
+
+	framework := forceFramework
+	if framework == "" {
+		detected, err := scan.DetectFramework()
+		if err != nil {
+			return nil, fmt.Errorf("error detecting framework: %w", err)
+		}
+		framework = detected
+	}
+
+	var services []scan.ServiceConfig
+	if detectServices {
+		services, _ = scan.DetectServices()
+	}
+
+	opts := appRenderOptions(framework, services, dir)
+	if name != "" && name != opts.ServiceName {
+		return nil, fmt.Errorf("no service named '%s', the detected application is '%s'", name, opts.ServiceName)
+	}
+	return render.TemplateData(opts, nil), nil
+}
+
+func init() {
+	rootCmd.AddCommand(templatesCmd)
+	templatesCmd.AddCommand(listTemplatesCmd)
+	templatesCmd.AddCommand(ejectTemplateCmd)
+	templatesCmd.AddCommand(templateDataCmd)
+
+	ejectTemplateCmd.Flags().BoolVar(&ejectForce, "force", false, "Overwrite the project template if it exists")
+}
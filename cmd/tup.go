@@ -30,7 +30,7 @@ var tupCmd = &cobra.Command{
 
 		// 1. Run the init command first to generate config files
 		fmt.Println("⏳ Initializing configuration...")
-		initProcess := exec.Command(os.Args[0], "init")
+		initProcess := exec.Command(os.Args[0], "init", "--out-dir", outputDir())
 		initProcess.Stdout = os.Stdout
 		initProcess.Stderr = os.Stderr
 
@@ -54,6 +54,7 @@ var tupCmd = &cobra.Command{
 			Detached:    detached,
 			ServiceName: serviceName,
 			DryRun:      dryRun,
+			OutputDir:   outputDir(),
 		}
 
 		var err error
@@ -87,7 +88,7 @@ var tupCmd = &cobra.Command{
 
 		// 3. Stop services and clean up
 		fmt.Println("\n🛑 Stopping services...")
-		stopProcess := exec.Command(os.Args[0], "stop")
+		stopProcess := exec.Command(os.Args[0], "stop", "--out-dir", outputDir())
 		stopProcess.Stdout = os.Stdout
 		stopProcess.Stderr = os.Stderr
 
@@ -105,14 +106,7 @@ var tupCmd = &cobra.Command{
 
 // cleanupFiles removes temporary configuration files
 func cleanupFiles() {
-	filesToClean := []string{"Dockerfile", "docker-compose.yml", "Tiltfile", "turbotilt.yaml"}
-
-	for _, file := range filesToClean {
-		cleanFile := exec.Command("powershell.exe", "-Command", fmt.Sprintf("Remove-Item -Path '%s' -ErrorAction SilentlyContinue", file))
-		if err := cleanFile.Run(); err != nil {
-			logger.Debug("Failed to clean file %s: %v", file, err)
-		}
-	}
+	cleanGeneratedFiles()
 }
 
 func init() {
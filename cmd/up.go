@@ -2,9 +2,11 @@ package cmd
 
 import (
 	"fmt"
+	"path/filepath"
 	"turbotilt/internal/config"
 	"turbotilt/internal/i18n"
 	"turbotilt/internal/logger"
+	"turbotilt/internal/render"
 	"turbotilt/internal/runtime"
 
 	"github.com/spf13/cobra"
@@ -16,6 +18,8 @@ var (
 	serviceName string
 	configFile  string
 	useMemory   bool
+	noRegen     bool
+	watchMode   bool
 )
 
 var upCmd = &cobra.Command{
@@ -47,16 +51,39 @@ var upCmd = &cobra.Command{
 			log.Info(t.Tr("Using services from memory selected with 'select' command"))
 		}
 
+		// Regenerate the files when the manifest or the project changed since their generation
+		dir := runOutputDir()
+		if !dryRun {
+			if err := ensureFreshFiles(dir, !noRegen); err != nil {
+				fmt.Printf("❌ Error regenerating files: %v\n", err)
+				return
+			}
+		}
+
 		// Define runtime options
 		opts := runtime.RunOptions{
 			UseTilt:     useTilt,
 			Detached:    detached,
-			TempFiles:   []string{"Dockerfile", "docker-compose.yml", "Tiltfile"},
+			TempFiles:   generatedFileCandidates(),
 			ServiceName: serviceName,
 			DryRun:      dryRun,
 			Debug:       debugMode,
 			ConfigFile:  configFile,
 			UseMemory:   useMemory,
+			OutputDir:   dir,
+		}
+
+		// Regenerate the files while running, Tilt reloads the Tiltfile and compose file when they change
+		if watchMode && !dryRun {
+			watcher := newUpWatcher(dir)
+			if err := watcher.Start(); err != nil {
+				fmt.Printf("⚠️ Unable to watch the project: %v\n", err)
+			} else {
+				defer watcher.Stop()
+				if !useTilt {
+					fmt.Println("ℹ️ Without Tilt, regenerated files are applied on the next 'turbotilt up'")
+				}
+			}
 		}
 
 		var err error
@@ -77,6 +104,14 @@ var upCmd = &cobra.Command{
 	},
 }
 
+// newUpWatcher creates the watcher regenerating the files of dir when their inputs change
+func newUpWatcher(dir string) *render.AutoUpdateWatcher {
+	return render.NewRegeneratingWatcher(filepath.Join(dir, "Tiltfile"), config.GenerationInputs, func() error {
+		recorded, _ := render.ReadFingerprint(dir)
+		return regenerateFiles(dir, recorded)
+	})
+}
+
 func init() {
 	rootCmd.AddCommand(upCmd)
 
@@ -85,5 +120,7 @@ func init() {
 	upCmd.Flags().StringVarP(&serviceName, "service", "s", "", "Start a specific service from the manifest (compatible with multi-service projects)")
 	upCmd.Flags().BoolVarP(&detached, "detach", "d", false, "Run in background (only for Docker Compose)")
 	upCmd.Flags().StringVarP(&configFile, "file", "f", "", "Path to the configuration file (if not specified, uses turbotilt.yaml or memory)")
+	upCmd.Flags().BoolVarP(&watchMode, "watch", "w", false, "Regenerate the files when the manifest, build files or application.* files change")
+	upCmd.Flags().BoolVar(&noRegen, "no-regen", false, "Only warn when the generated files are stale instead of regenerating them")
 	upCmd.Flags().BoolVarP(&useMemory, "memory", "m", true, "Use services selected with the select command stored in memory")
 }
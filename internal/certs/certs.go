@@ -0,0 +1,322 @@
+package certs
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// CAFile is the certificate of the development CA, to add to the trusted authorities
+	CAFile = "ca.pem"
+
+	// CAKeyFile is the private key of the development CA
+	CAKeyFile = "ca-key.pem"
+
+	// caValidity is the validity of the development CA
+	caValidity = 10 * 365 * 24 * time.Hour
+
+	// certificateValidity is the validity of the issued certificates, the maximum accepted by browsers
+	certificateValidity = 825 * 24 * time.Hour
+)
+
+// Authority is the development certificate authority issuing the certificates of the services
+type Authority struct {
+	Certificate *x509.Certificate
+	Key         crypto.Signer
+	Dir         string // Directory containing CAFile and CAKeyFile
+}
+
+// Files contains the paths of an issued certificate
+type Files struct {
+	Certificate string // PEM certificate
+	Key         string // PEM private key (PKCS#8)
+	Bundle      string // PEM private key followed by the certificate
+}
+
+// UserCADir returns the directory of the development CA, shared by all the projects of the user
+// so that it only needs to be trusted once
+func UserCADir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "turbotilt", "ca")
+}
+
+// LoadOrCreateAuthority loads the CA of dir, creating it when it does not exist.
+// created reports whether a new CA was created, which then needs to be trusted.
+func LoadOrCreateAuthority(dir string) (authority *Authority, created bool, err error) {
+	authority, err = loadAuthority(dir)
+	if err == nil {
+		return authority, false, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, false, err
+	}
+
+	authority, err = createAuthority(dir)
+	if err != nil {
+		return nil, false, err
+	}
+	return authority, true, nil
+}
+
+// loadAuthority reads the CA certificate and key of dir
+func loadAuthority(dir string) (*Authority, error) {
+	certPEM, err := os.ReadFile(filepath.Join(dir, CAFile))
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := os.ReadFile(filepath.Join(dir, CAKeyFile))
+	if err != nil {
+		return nil, err
+	}
+
+	certBlock, _ := pem.Decode(certPEM)
+	if certBlock == nil {
+		return nil, fmt.Errorf("%s is not a PEM certificate", filepath.Join(dir, CAFile))
+	}
+	certificate, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", filepath.Join(dir, CAFile), err)
+	}
+
+	keyBlock, _ := pem.Decode(keyPEM)
+	if keyBlock == nil {
+		return nil, fmt.Errorf("%s is not a PEM private key", filepath.Join(dir, CAKeyFile))
+	}
+	key, err := x509.ParsePKCS8PrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", filepath.Join(dir, CAKeyFile), err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a signing key", filepath.Join(dir, CAKeyFile))
+	}
+
+	return &Authority{Certificate: certificate, Key: signer, Dir: dir}, nil
+}
+
+// createAuthority creates a new CA in dir
+func createAuthority(dir string) (*Authority, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("error generating the CA key: %w", err)
+	}
+
+	serial, err := serialNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	hostname, _ := os.Hostname()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization:       []string{"Turbotilt development CA"},
+			OrganizationalUnit: []string{hostname},
+			CommonName:         "Turbotilt development CA",
+		},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, fmt.Errorf("error creating the CA certificate: %w", err)
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("error creating directory %s: %w", dir, err)
+	}
+	keyPEM, err := encodeKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, CAKeyFile), keyPEM, 0600); err != nil {
+		return nil, fmt.Errorf("error writing the CA key: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, CAFile), encodeCertificate(der), 0644); err != nil {
+		return nil, fmt.Errorf("error writing the CA certificate: %w", err)
+	}
+
+	return &Authority{Certificate: certificate, Key: key, Dir: dir}, nil
+}
+
+// Issue creates the certificate of a service for the given hostnames and IP addresses,
+// written to <dir>/<name>.pem, <name>-key.pem and <name>-bundle.pem
+func (a *Authority) Issue(dir, name string, hosts []string) (Files, error) {
+	files := CertificateFiles(dir, name)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return files, fmt.Errorf("error generating the key of %s: %w", name, err)
+	}
+
+	serial, err := serialNumber()
+	if err != nil {
+		return files, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization: []string{"Turbotilt development certificate"},
+			CommonName:   name,
+		},
+		NotBefore:   time.Now().Add(-time.Hour),
+		NotAfter:    time.Now().Add(certificateValidity),
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, a.Certificate, &key.PublicKey, a.Key)
+	if err != nil {
+		return files, fmt.Errorf("error creating the certificate of %s: %w", name, err)
+	}
+
+	keyPEM, err := encodeKey(key)
+	if err != nil {
+		return files, err
+	}
+	certPEM := encodeCertificate(der)
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return files, fmt.Errorf("error creating directory %s: %w", dir, err)
+	}
+	// The key and the bundle are mounted in the application containers, whose user is not necessarily
+	// the owner of the files (Buildpacks images, USER of a Dockerfile): they are readable by all, unlike
+	// the CA key which is never mounted
+	for path, content := range map[string][]byte{
+		files.Key:         keyPEM,
+		files.Certificate: certPEM,
+		files.Bundle:      append(keyPEM, certPEM...),
+	} {
+		if err := writeReadable(path, content); err != nil {
+			return files, err
+		}
+	}
+	return files, nil
+}
+
+// writeReadable writes a file readable by all, including a file written with another mode before
+func writeReadable(path string, content []byte) error {
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+	if err := os.Chmod(path, 0644); err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+	return nil
+}
+
+// CertificateFiles returns the paths of the certificate of a service in dir
+func CertificateFiles(dir, name string) Files {
+	return Files{
+		Certificate: filepath.Join(dir, name+".pem"),
+		Key:         filepath.Join(dir, name+"-key.pem"),
+		Bundle:      filepath.Join(dir, name+"-bundle.pem"),
+	}
+}
+
+// Exists reports whether the certificate of a service was issued in dir
+func Exists(dir, name string) bool {
+	files := CertificateFiles(dir, name)
+	for _, path := range []string{files.Certificate, files.Key, files.Bundle} {
+		if _, err := os.Stat(path); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Matches reports whether the certificate of a service issued in dir covers exactly
+// the given hostnames and IP addresses, an unreadable certificate never matches
+func Matches(dir, name string, hosts []string) bool {
+	content, err := os.ReadFile(CertificateFiles(dir, name).Certificate)
+	if err != nil {
+		return false
+	}
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return false
+	}
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return false
+	}
+
+	issued := make(map[string]bool)
+	for _, host := range certificate.DNSNames {
+		issued[host] = true
+	}
+	for _, ip := range certificate.IPAddresses {
+		issued[ip.String()] = true
+	}
+	wanted := make(map[string]bool)
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			host = ip.String()
+		}
+		wanted[host] = true
+	}
+	if len(issued) != len(wanted) {
+		return false
+	}
+	for host := range wanted {
+		if !issued[host] {
+			return false
+		}
+	}
+	return true
+}
+
+// serialNumber returns a random certificate serial number
+func serialNumber() (*big.Int, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("error generating a serial number: %w", err)
+	}
+	return serial, nil
+}
+
+// encodeKey encodes a private key in PEM (PKCS#8)
+func encodeKey(key crypto.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding the private key: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// encodeCertificate encodes a certificate in PEM
+func encodeCertificate(der []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
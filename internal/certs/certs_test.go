@@ -0,0 +1,102 @@
+package certs
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIssueCertificate(t *testing.T) {
+	caDir := filepath.Join(t.TempDir(), "ca")
+
+	authority, created, err := LoadOrCreateAuthority(caDir)
+	if err != nil {
+		t.Fatalf("LoadOrCreateAuthority returned an error: %v", err)
+	}
+	if !created {
+		t.Error("The CA should be created on first use")
+	}
+
+	// The CA is reused afterwards
+	reloaded, created, err := LoadOrCreateAuthority(caDir)
+	if err != nil {
+		t.Fatalf("LoadOrCreateAuthority returned an error: %v", err)
+	}
+	if created || !reloaded.Certificate.Equal(authority.Certificate) {
+		t.Error("The existing CA should be reused")
+	}
+
+	certsDir := filepath.Join(t.TempDir(), "certs")
+	files, err := reloaded.Issue(certsDir, "gateway", []string{"localhost", "127.0.0.1", "api.localhost"})
+	if err != nil {
+		t.Fatalf("Issue returned an error: %v", err)
+	}
+	if !Exists(certsDir, "gateway") {
+		t.Error("The certificate files should exist")
+	}
+
+	content, err := os.ReadFile(files.Certificate)
+	if err != nil {
+		t.Fatalf("Unable to read the certificate: %v", err)
+	}
+	block, _ := pem.Decode(content)
+	if block == nil {
+		t.Fatal("The certificate is not PEM encoded")
+	}
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("Unable to parse the certificate: %v", err)
+	}
+
+	// The certificate is trusted through the CA for each of its hostnames
+	roots := x509.NewCertPool()
+	roots.AddCert(authority.Certificate)
+	for _, host := range []string{"api.localhost", "localhost", "127.0.0.1"} {
+		if _, err := certificate.Verify(x509.VerifyOptions{DNSName: host, Roots: roots}); err != nil {
+			t.Errorf("The certificate should be valid for %s: %v", host, err)
+		}
+	}
+	if _, err := certificate.Verify(x509.VerifyOptions{DNSName: "other.localhost", Roots: roots}); err == nil {
+		t.Error("The certificate should not be valid for other.localhost")
+	}
+}
+
+func TestIssuedCertificateMatchesHosts(t *testing.T) {
+	authority, _, err := LoadOrCreateAuthority(filepath.Join(t.TempDir(), "ca"))
+	if err != nil {
+		t.Fatalf("LoadOrCreateAuthority returned an error: %v", err)
+	}
+
+	certsDir := filepath.Join(t.TempDir(), "certs")
+	if Matches(certsDir, "gateway", []string{"localhost"}) {
+		t.Error("A missing certificate should not match")
+	}
+	files, err := authority.Issue(certsDir, "gateway", []string{"localhost", "127.0.0.1"})
+	if err != nil {
+		t.Fatalf("Issue returned an error: %v", err)
+	}
+
+	if !Matches(certsDir, "gateway", []string{"127.0.0.1", "localhost"}) {
+		t.Error("The certificate should match the same hosts in any order")
+	}
+	if Matches(certsDir, "gateway", []string{"localhost", "127.0.0.1", "api.localhost"}) {
+		t.Error("The certificate should not match when a host is added")
+	}
+	if Matches(certsDir, "gateway", []string{"localhost"}) {
+		t.Error("The certificate should not match when a host is removed")
+	}
+
+	// The service keys are readable by the users of the application containers, the CA key is not mounted
+	modes := map[string]os.FileMode{files.Key: 0644, files.Bundle: 0644, filepath.Join(authority.Dir, CAKeyFile): 0600}
+	for path, mode := range modes {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("Unable to stat %s: %v", path, err)
+		}
+		if info.Mode().Perm() != mode {
+			t.Errorf("%s should have mode %v, got %v", path, mode, info.Mode().Perm())
+		}
+	}
+}
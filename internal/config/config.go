@@ -4,17 +4,20 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"gopkg.in/yaml.v3"
 
 	"turbotilt/internal/render"
+	"turbotilt/internal/scan"
 )
 
 // Constants for file names
 const (
 	LegacyConfigFileName = "turbotilt.yml"
 	ManifestFileName     = "turbotilt.yaml"
+	DefaultOutputDir     = ".turbotilt" // Directory receiving the generated files by default
 )
 
 // Config represents the Turbotilt configuration
@@ -65,23 +68,318 @@ type ServiceConfig struct {
 
 // Manifest represents the new declarative structure of the turbotilt.yaml file
 type Manifest struct {
-	Services []ManifestService `yaml:"services"`
+	Output        string               `yaml:"output,omitempty"` // Directory receiving the generated files
+	Services      []ManifestService    `yaml:"services"`
+	Observability *ObservabilityConfig `yaml:"observability,omitempty"` // Observability stack added to the environment
+	Gateway       *GatewayConfig       `yaml:"gateway,omitempty"`       // Gateway routing <service>.localhost to the services
+	TLS           bool                 `yaml:"tls,omitempty"`           // Serve the services over HTTPS (see turbotilt certs)
+	Tools         []string             `yaml:"tools,omitempty"`         // Administration tools (adminer, pgadmin, kafka-ui, etc.)
+	Seed          *SeedConfig          `yaml:"seed,omitempty"`          // Migrations and seed scripts run before the application services
+	Chaos         *ChaosConfig         `yaml:"chaos,omitempty"`         // Toxiproxy between the application services and dependent services
+	Stubs         *StubsConfig         `yaml:"stubs,omitempty"`         // WireMock services replacing the third-party HTTP APIs
+}
+
+// StubsConfig replaces the third-party HTTP APIs set in the URL properties of the application services
+// (payments.base-url...) with a WireMock service per external host, serving the stubs of the project
+type StubsConfig struct {
+	Dir   string   `yaml:"dir,omitempty"`   // Directory of the stubs, stubs/<host> for a host or shared (stubs by default)
+	Hosts []string `yaml:"hosts,omitempty"` // External hosts replaced (all the detected ones by default)
+}
+
+// ChaosConfig inserts Toxiproxy between the application services and some dependent services,
+// whose faults are then driven by turbotilt chaos
+type ChaosConfig struct {
+	Services []string `yaml:"services"`       // Types of the dependent services proxied
+	Port     string   `yaml:"port,omitempty"` // Host port of the Toxiproxy API (8474 by default)
+}
+
+// SeedConfig runs the migrations and the SQL seed scripts against a database before the application
+// services start, as one-shot compose services or as a Tilt local_resource running a command
+type SeedConfig struct {
+	Database   string   `yaml:"database,omitempty"`   // Type of the dependent service seeded (the first JDBC database by default)
+	Tool       string   `yaml:"tool,omitempty"`       // flyway, liquibase (detected if empty)
+	Migrations string   `yaml:"migrations,omitempty"` // Migration directory (detected if empty)
+	Changelog  string   `yaml:"changelog,omitempty"`  // Liquibase master changelog, relative to the migration directory (detected if empty)
+	Scripts    []string `yaml:"scripts,omitempty"`    // SQL scripts run after the migrations
+	Command    string   `yaml:"command,omitempty"`    // Command run on the host by a Tilt local_resource instead
+}
+
+// GatewayConfig adds a Traefik gateway giving every application service a <service>.localhost hostname
+type GatewayConfig struct {
+	Enabled bool                 `yaml:"enabled"`
+	Port    string               `yaml:"port,omitempty"`    // Host port of the gateway (80 by default)
+	TLSPort string               `yaml:"tlsPort,omitempty"` // HTTPS host port of the gateway (443 by default)
+	Routes  []GatewayRouteConfig `yaml:"routes,omitempty"`  // Path routes between services
+}
+
+// GatewayRouteConfig sends the requests for a path of a service hostname to another service,
+// typically /api of the frontend to a backend
+type GatewayRouteConfig struct {
+	Host        string `yaml:"host"`    // Service whose hostname receives the route
+	Path        string `yaml:"path"`    // Path prefix
+	Service     string `yaml:"service"` // Service receiving the requests
+	StripPrefix bool   `yaml:"stripPrefix,omitempty"`
+}
+
+// ObservabilityConfig adds an OpenTelemetry collector, Jaeger, Prometheus and Grafana to the environment
+// and instruments the application services with the OpenTelemetry Java agent
+type ObservabilityConfig struct {
+	Enabled      bool   `yaml:"enabled"`
+	AgentVersion string `yaml:"agentVersion,omitempty"` // OpenTelemetry Java agent version
+}
+
+// GatewayOptions returns the render options of the gateway of the manifest
+func (m Manifest) GatewayOptions() render.GatewayOptions {
+	if m.Gateway == nil {
+		return render.GatewayOptions{}
+	}
+
+	options := render.GatewayOptions{
+		Enabled: m.Gateway.Enabled,
+		Port:    m.Gateway.Port,
+		TLSPort: m.Gateway.TLSPort,
+	}
+	for _, route := range m.Gateway.Routes {
+		options.Routes = append(options.Routes, render.GatewayRoute{
+			Host:        route.Host,
+			Path:        route.Path,
+			Service:     route.Service,
+			StripPrefix: route.StripPrefix,
+		})
+	}
+	return options
+}
+
+// TLSOptions returns the render options of HTTPS of the manifest
+func (m Manifest) TLSOptions() render.TLSOptions {
+	return render.TLSOptions{Enabled: m.TLS}
+}
+
+// ObservabilityOptions returns the render options of the observability stack of the manifest
+func (m Manifest) ObservabilityOptions() render.ObservabilityOptions {
+	if m.Observability == nil {
+		return render.ObservabilityOptions{}
+	}
+	return render.ObservabilityOptions{
+		Enabled:      m.Observability.Enabled,
+		AgentVersion: m.Observability.AgentVersion,
+	}
+}
+
+// SeedOptions returns the render options of the seeding of the manifest. The migration tool and
+// directory not set are detected in the application services.
+func (m Manifest) SeedOptions() render.SeedOptions {
+	if m.Seed == nil {
+		return render.SeedOptions{}
+	}
+
+	options := render.SeedOptions{
+		Enabled:    true,
+		Database:   m.Seed.Database,
+		Tool:       strings.ToLower(m.Seed.Tool),
+		Migrations: m.Seed.Migrations,
+		Changelog:  m.Seed.Changelog,
+		Scripts:    m.Seed.Scripts,
+		Command:    m.Seed.Command,
+	}
+	if options.Migrations == "" {
+		for _, service := range m.Services {
+			if service.Runtime == "" {
+				continue
+			}
+			if migrations, ok := scan.DetectMigrations(service.Path); ok && (options.Tool == "" || options.Tool == migrations.Tool) {
+				options.Tool = migrations.Tool
+				options.Migrations = migrations.Dir
+				if options.Changelog == "" {
+					options.Changelog = migrations.Changelog
+				}
+				break
+			}
+		}
+	}
+	return options
+}
+
+// ChaosOptions returns the render options of Toxiproxy of the manifest
+func (m Manifest) ChaosOptions() render.ChaosOptions {
+	if m.Chaos == nil {
+		return render.ChaosOptions{}
+	}
+
+	options := render.ChaosOptions{Enabled: true, Port: m.Chaos.Port}
+	for _, service := range m.Chaos.Services {
+		options.Services = append(options.Services, strings.ToLower(service))
+	}
+	return options
+}
+
+// StubOptions returns the render options of the WireMock stubs of the manifest
+func (m Manifest) StubOptions() render.StubOptions {
+	if m.Stubs == nil {
+		return render.StubOptions{}
+	}
+	return render.StubOptions{Enabled: true, Dir: m.Stubs.Dir, Hosts: m.Stubs.Hosts}
+}
+
+// EnvironmentSections returns the sections of the manifest set that apply to its application services
+// (observability, gateway...), which cannot be generated without an application declared with a runtime
+func (m Manifest) EnvironmentSections() []string {
+	var sections []string
+	for _, section := range []struct {
+		name string
+		set  bool
+	}{
+		{"observability", m.ObservabilityOptions().Enabled},
+		{"gateway", m.GatewayOptions().Enabled},
+		{"tls", m.TLS},
+		{"tools", len(m.Tools) > 0},
+		{"seed", m.Seed != nil},
+		{"chaos", m.Chaos != nil},
+		{"stubs", m.Stubs != nil},
+	} {
+		if section.set {
+			sections = append(sections, section.name)
+		}
+	}
+	return sections
+}
+
+// DependencyServices returns the dependent services (with a type) declared in the manifest
+func (m Manifest) DependencyServices() []scan.ServiceConfig {
+	var services []scan.ServiceConfig
+	for _, service := range m.Services {
+		if service.Runtime != "" || service.Type == "" {
+			continue
+		}
+		services = append(services, service.dependency())
+	}
+	return services
+}
+
+// ApplicationDependencies returns, by application service name, the dependent services the application
+// uses: the ones named in its 'dependencies', or else the ones detected in its directory. The dependent
+// services used by no application are given to all of them, so that they stay in the environment.
+func (m Manifest) ApplicationDependencies() map[string][]scan.ServiceConfig {
+	var dependencies []ManifestService
+	for _, service := range m.Services {
+		if service.Runtime == "" && service.Type != "" {
+			dependencies = append(dependencies, service)
+		}
+	}
+
+	used := make(map[int]bool)
+	attached := make(map[string]map[int]bool)
+	for _, service := range m.Services {
+		if service.Runtime == "" {
+			continue
+		}
+		wanted := service.usedDependencies()
+		attached[service.Name] = make(map[int]bool)
+		for i, dependency := range dependencies {
+			if wanted[strings.ToLower(dependency.Name)] || wanted[render.CanonicalServiceType(dependency.Type)] {
+				used[i] = true
+				attached[service.Name][i] = true
+			}
+		}
+	}
+
+	result := make(map[string][]scan.ServiceConfig, len(attached))
+	for name, indexes := range attached {
+		for i, dependency := range dependencies {
+			if indexes[i] || !used[i] {
+				result[name] = append(result[name], dependency.dependency())
+			}
+		}
+	}
+	return result
+}
+
+// usedDependencies returns the names or types of the dependent services used by an application
+// service: its 'dependencies', or else the types detected in its directory
+func (s ManifestService) usedDependencies() map[string]bool {
+	wanted := make(map[string]bool)
+	if len(s.Dependencies) > 0 {
+		for _, name := range s.Dependencies {
+			wanted[strings.ToLower(name)] = true
+			wanted[render.CanonicalServiceType(name)] = true
+		}
+		return wanted
+	}
+	detected, _ := scan.DetectServicesIn(s.Path)
+	for _, service := range detected {
+		wanted[render.CanonicalServiceType(string(service.Type))] = true
+	}
+	return wanted
+}
+
+// dependency converts a dependent service of the manifest to its scan configuration
+func (s ManifestService) dependency() scan.ServiceConfig {
+	dependency := scan.ServiceConfig{
+		Type:        scan.ServiceType(strings.ToLower(s.Type)),
+		Version:     s.Version,
+		Port:        s.Port,
+		Credentials: s.Env,
+		Volumes:     s.Volumes,
+		Resources:   s.Resources(),
+		Seeds:       s.SeedFiles,
+		Dir:         s.Path,
+		Mounts:      make(map[string]string, len(s.Mounts)+1),
+	}
+	for name, source := range s.Mounts {
+		dependency.Mounts[name] = source
+	}
+
+	// The seed files are looked up in the directory of the service, and the project files of the mounts
+	// of its recipe (realm exports, definitions, config repository...)
+	if s.Path != "" && len(dependency.Seeds) == 0 {
+		dependency.Seeds = scan.DetectSeedFiles(s.Path, dependency.Type)
+	}
+	if s.Definitions != "" {
+		dependency.Mounts["definitions"] = s.Definitions
+	}
+	return dependency
 }
 
 // ManifestService represents a service in the declarative manifest
 type ManifestService struct {
-	Name       string            `yaml:"name"`
-	Path       string            `yaml:"path"`
-	Java       string            `yaml:"java,omitempty"`
-	Build      string            `yaml:"build,omitempty"`   // maven, gradle
-	Runtime    string            `yaml:"runtime,omitempty"` // spring, quarkus, micronaut
-	Port       string            `yaml:"port,omitempty"`
-	DevMode    bool              `yaml:"devMode,omitempty"`
-	Type       string            `yaml:"type,omitempty"`       // For dependent services: mysql, postgres, etc.
-	Version    string            `yaml:"version,omitempty"`    // For dependent services
-	Env        map[string]string `yaml:"env,omitempty"`        // Environment variables
-	Volumes    []string          `yaml:"volumes,omitempty"`    // Volume mounts
-	WatchPaths []string          `yaml:"watchPaths,omitempty"` // Paths to watch for live reload
+	Name         string            `yaml:"name"`
+	Path         string            `yaml:"path"`
+	Java         string            `yaml:"java,omitempty"`
+	Build        string            `yaml:"build,omitempty"`   // maven, gradle
+	Runtime      string            `yaml:"runtime,omitempty"` // spring, quarkus, micronaut
+	Port         string            `yaml:"port,omitempty"`
+	DevMode      bool              `yaml:"devMode,omitempty"`
+	Type         string            `yaml:"type,omitempty"`         // For dependent services: mysql, postgres, etc.
+	Version      string            `yaml:"version,omitempty"`      // For dependent services
+	Env          map[string]string `yaml:"env,omitempty"`          // Environment variables
+	Volumes      []string          `yaml:"volumes,omitempty"`      // Volume mounts
+	WatchPaths   []string          `yaml:"watchPaths,omitempty"`   // Paths to watch for live reload
+	ImageBuild   string            `yaml:"imageBuild,omitempty"`   // dockerfile, existing-dockerfile, jib, buildpacks (detected if empty)
+	Debug        bool              `yaml:"debug,omitempty"`        // Start the JVM with the JDWP agent
+	DebugPort    string            `yaml:"debugPort,omitempty"`    // Host port of the JDWP agent (assigned if empty)
+	Buckets      []string          `yaml:"buckets,omitempty"`      // Buckets created at startup (minio, localstack)
+	Queues       []string          `yaml:"queues,omitempty"`       // Queues created at startup (localstack, rabbitmq)
+	Topics       []string          `yaml:"topics,omitempty"`       // Topics created at startup (kafka, localstack)
+	Tables       []string          `yaml:"tables,omitempty"`       // Tables created at startup (localstack)
+	Definitions  string            `yaml:"definitions,omitempty"`  // Definitions export imported by the init service (rabbitmq), the 'definitions' mount
+	Mounts       map[string]string `yaml:"mounts,omitempty"`       // Project files of the mounts of the recipe, by mount name (detected if empty)
+	SeedFiles    []string          `yaml:"seedFiles,omitempty"`    // Seed files loaded at startup (mongodb, redis)
+	Dependencies []string          `yaml:"dependencies,omitempty"` // Names or types of the dependent services used by an application (detected if empty)
+}
+
+// Resources returns the resources created when a dependent service starts: the buckets,
+// then the other resources prefixed with their kind (queue:orders...)
+func (s ManifestService) Resources() []string {
+	resources := append([]string(nil), s.Buckets...)
+	for _, kind := range []struct {
+		Name  string
+		Names []string
+	}{{"queue", s.Queues}, {"topic", s.Topics}, {"table", s.Tables}} {
+		for _, name := range kind.Names {
+			resources = append(resources, kind.Name+":"+name)
+		}
+	}
+	return resources
 }
 
 // DefaultConfig creates a default configuration
@@ -151,6 +449,12 @@ func ValidateManifestSchema(manifest Manifest) error {
 		return fmt.Errorf("the manifest must contain at least one service")
 	}
 
+	// Invalid user or project recipes would make their service types unknown
+	if _, err := render.LoadRecipes(); err != nil {
+		return err
+	}
+
+	debugPorts := make(map[string]string)
 	for i, service := range manifest.Services {
 		if service.Name == "" {
 			return fmt.Errorf("service #%d: name is required", i+1)
@@ -172,8 +476,133 @@ func ValidateManifestSchema(manifest Manifest) error {
 		if service.Type != "" && !isValidServiceType(service.Type) {
 			return fmt.Errorf("service '%s': type '%s' not supported", service.Name, service.Type)
 		}
+
+		if service.ImageBuild != "" && !scan.IsValidImageBuildStrategy(service.ImageBuild) {
+			return fmt.Errorf("service '%s': imageBuild '%s' not supported", service.Name, service.ImageBuild)
+		}
+
+		if err := validateDependencies(manifest, service); err != nil {
+			return err
+		}
+
+		if service.DebugPort != "" {
+			if _, err := strconv.Atoi(service.DebugPort); err != nil {
+				return fmt.Errorf("service '%s': debugPort '%s' is not a port number", service.Name, service.DebugPort)
+			}
+			if other, used := debugPorts[service.DebugPort]; used {
+				return fmt.Errorf("service '%s': debugPort %s is already used by '%s'", service.Name, service.DebugPort, other)
+			}
+			debugPorts[service.DebugPort] = service.Name
+		}
 	}
 
+	for _, tool := range manifest.Tools {
+		if !render.IsValidTool(tool) {
+			return fmt.Errorf("tool '%s' not supported (%s)", tool, strings.Join(render.ToolNames(), ", "))
+		}
+	}
+
+	if err := validateSeed(manifest); err != nil {
+		return err
+	}
+	if err := validateChaos(manifest); err != nil {
+		return err
+	}
+	return validateGateway(manifest)
+}
+
+// validateSeed checks the migration tool and the database of the seeding
+func validateSeed(manifest Manifest) error {
+	if manifest.Seed == nil {
+		return nil
+	}
+	if manifest.Seed.Tool != "" && !render.IsValidMigrationTool(manifest.Seed.Tool) {
+		return fmt.Errorf("seed: tool '%s' not supported (flyway, liquibase)", manifest.Seed.Tool)
+	}
+	if manifest.Seed.Migrations != "" && manifest.Seed.Tool == "" {
+		return fmt.Errorf("seed: tool is required with migrations")
+	}
+	if manifest.Seed.Database == "" {
+		return nil
+	}
+	for _, service := range manifest.Services {
+		if service.Type != "" && strings.EqualFold(service.Type, manifest.Seed.Database) {
+			return nil
+		}
+	}
+	return fmt.Errorf("seed: database '%s' is not a dependent service", manifest.Seed.Database)
+}
+
+// validateDependencies checks that the dependencies of an application service name dependent services
+func validateDependencies(manifest Manifest, service ManifestService) error {
+	if len(service.Dependencies) > 0 && service.Runtime == "" {
+		return fmt.Errorf("service '%s': dependencies are only set on application services", service.Name)
+	}
+	for _, name := range service.Dependencies {
+		found := false
+		for _, dependency := range manifest.Services {
+			if dependency.Runtime == "" && dependency.Type != "" &&
+				(strings.EqualFold(dependency.Name, name) || render.CanonicalServiceType(dependency.Type) == render.CanonicalServiceType(name)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("service '%s': dependency '%s' is not a dependent service", service.Name, name)
+		}
+	}
+	return nil
+}
+
+// validateChaos checks that the services proxied by Toxiproxy are dependent services
+func validateChaos(manifest Manifest) error {
+	if manifest.Chaos == nil {
+		return nil
+	}
+	if len(manifest.Chaos.Services) == 0 {
+		return fmt.Errorf("chaos: no service to proxy")
+	}
+	if manifest.Chaos.Port != "" {
+		if _, err := strconv.Atoi(manifest.Chaos.Port); err != nil {
+			return fmt.Errorf("chaos: port '%s' is not a port number", manifest.Chaos.Port)
+		}
+	}
+
+	types := make(map[string]bool)
+	for _, service := range manifest.Services {
+		types[strings.ToLower(service.Type)] = true
+	}
+	for _, name := range manifest.Chaos.Services {
+		if !types[strings.ToLower(name)] {
+			return fmt.Errorf("chaos: '%s' is not a dependent service", name)
+		}
+	}
+	return nil
+}
+
+// validateGateway checks that the routes of the gateway link application services
+func validateGateway(manifest Manifest) error {
+	if manifest.Gateway == nil {
+		return nil
+	}
+
+	appServices := make(map[string]bool)
+	for _, service := range manifest.Services {
+		if service.Runtime != "" {
+			appServices[service.Name] = true
+		}
+	}
+
+	for i, route := range manifest.Gateway.Routes {
+		if !strings.HasPrefix(route.Path, "/") {
+			return fmt.Errorf("gateway route #%d: path '%s' must start with '/'", i+1, route.Path)
+		}
+		for _, name := range []string{route.Host, route.Service} {
+			if !appServices[name] {
+				return fmt.Errorf("gateway route #%d: '%s' is not an application service", i+1, name)
+			}
+		}
+	}
 	return nil
 }
 
@@ -188,19 +617,9 @@ func isValidRuntime(runtime string) bool {
 	return validRuntimes[strings.ToLower(runtime)]
 }
 
-// isValidServiceType checks if the specified service type is supported
+// isValidServiceType checks if a recipe exists for the specified service type
 func isValidServiceType(serviceType string) bool {
-	validTypes := map[string]bool{
-		"mysql":         true,
-		"postgresql":    true,
-		"postgres":      true,
-		"redis":         true,
-		"mongodb":       true,
-		"kafka":         true,
-		"rabbitmq":      true,
-		"elasticsearch": true,
-	}
-	return validTypes[strings.ToLower(serviceType)]
+	return render.IsKnownServiceType(serviceType)
 }
 
 // GenerateManifestFromConfig generates a declarative manifest from a config
@@ -245,12 +664,17 @@ func ConvertManifestToRenderOptions(service ManifestService) (*render.Options, e
 	}
 
 	opts := &render.Options{
-		ServiceName: service.Name,
-		AppName:     service.Name,
-		Framework:   service.Runtime,
-		Port:        service.Port,
-		Path:        service.Path,
-		DevMode:     service.DevMode,
+		ServiceName:   service.Name,
+		AppName:       service.Name,
+		Framework:     service.Runtime,
+		Port:          service.Port,
+		Path:          service.Path,
+		DevMode:       service.DevMode,
+		BuildSystem:   service.Build,
+		BuildStrategy: scan.ImageBuildStrategy(strings.ToLower(service.ImageBuild)),
+		WatchPaths:    service.WatchPaths,
+		Debug:         service.Debug,
+		DebugPort:     service.DebugPort,
 	}
 
 	// Set default values if not specified
@@ -279,12 +703,30 @@ func SaveConfig(config Config, path string) error {
 
 // SaveManifest saves the manifest to a file
 func SaveManifest(manifest Manifest, path string) error {
+	return SaveManifestSuggestingStubs(manifest, path, nil)
+}
+
+// SaveManifestSuggestingStubs saves a manifest followed by a stubs section listing the external hosts
+// detected, commented out: no host is rerouted to WireMock until the ones to stub are uncommented
+func SaveManifestSuggestingStubs(manifest Manifest, path string, hosts []string) error {
 	data, err := yaml.Marshal(manifest)
 	if err != nil {
 		return err
 	}
+	if len(hosts) > 0 {
+		var sb strings.Builder
+		sb.WriteString("\n# External APIs detected, uncomment the stubs section and the hosts to replace with WireMock stubs\n")
+		sb.WriteString("# stubs:\n")
+		sb.WriteString(fmt.Sprintf("#   dir: %s\n", render.DefaultStubsDir))
+		sb.WriteString("#   hosts:\n")
+		for _, host := range hosts {
+			sb.WriteString(fmt.Sprintf("#     - %s\n", host))
+		}
+		data = append(data, sb.String()...)
+	}
 
-	return os.WriteFile(path, data, 0644)
+	// Written like the generated files, so that it can be previewed
+	return render.WriteFile(path, data)
 }
 
 // FindConfiguration searches for the configuration or manifest file in the current directory
@@ -303,6 +745,23 @@ func FindConfiguration() (string, bool, error) {
 	return "", false, fmt.Errorf("no configuration file found (neither %s nor %s)", ManifestFileName, LegacyConfigFileName)
 }
 
+// ResolveOutputDir returns the directory receiving the generated files: the override
+// (--out-dir flag) when set, then the output declared in the manifest, then DefaultOutputDir
+func ResolveOutputDir(override string) string {
+	if override != "" {
+		return filepath.Clean(override)
+	}
+
+	if data, err := os.ReadFile(ManifestFileName); err == nil {
+		var manifest Manifest
+		if err := yaml.Unmarshal(data, &manifest); err == nil && manifest.Output != "" {
+			return filepath.Clean(manifest.Output)
+		}
+	}
+
+	return DefaultOutputDir
+}
+
 // currentDir returns the current directory
 func currentDir() string {
 	dir, err := os.Getwd()
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+
+	"turbotilt/internal/render"
+)
+
+// applicationConfigExtensions are the extensions of the application.* configuration files
+var applicationConfigExtensions = map[string]bool{".properties": true, ".yml": true, ".yaml": true}
+
+// GenerationInputs returns the files the generated files depend on: the manifest, the customized
+// templates and recipes and, for every application service, its build files, project Dockerfile, environment
+// file and application.* files
+func GenerationInputs() []string {
+	inputs := []string{ManifestFileName, LegacyConfigFileName}
+	for _, dir := range render.TemplateSearchPath() {
+		templates, _ := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+		inputs = append(inputs, templates...)
+	}
+	for _, dir := range render.RecipeSearchPath() {
+		for _, pattern := range []string{"*.yaml", "*.yml"} {
+			recipes, _ := filepath.Glob(filepath.Join(dir, pattern))
+			inputs = append(inputs, recipes...)
+		}
+	}
+	for _, servicePath := range applicationServicePaths() {
+		inputs = append(inputs, ServiceInputs(servicePath)...)
+	}
+	return inputs
+}
+
+// ServiceInputs returns the files of a service the generated files depend on
+func ServiceInputs(servicePath string) []string {
+	inputs := []string{
+		filepath.Join(servicePath, "pom.xml"),
+		filepath.Join(servicePath, "build.gradle"),
+		filepath.Join(servicePath, "build.gradle.kts"),
+		filepath.Join(servicePath, "envs", "local.env"),
+	}
+
+	// Only a Dockerfile provided by the project is an input, not a generated one
+	dockerfile := filepath.Join(servicePath, "Dockerfile")
+	if ownership, err := render.CheckOwnership(dockerfile); err == nil && ownership == render.FileForeign {
+		inputs = append(inputs, dockerfile)
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(servicePath, "src", "main", "resources", "application*"))
+	for _, match := range matches {
+		if applicationConfigExtensions[strings.ToLower(filepath.Ext(match))] {
+			inputs = append(inputs, match)
+		}
+	}
+
+	return inputs
+}
+
+// applicationServicePaths returns the paths of the application services of the manifest,
+// or the current directory when there is no manifest
+func applicationServicePaths() []string {
+	manifest, err := LoadManifest(ManifestFileName)
+	if err != nil {
+		return []string{"."}
+	}
+
+	var paths []string
+	for _, service := range manifest.Services {
+		if service.Runtime == "" {
+			continue
+		}
+		path := service.Path
+		if path == "" {
+			path = "."
+		}
+		paths = append(paths, path)
+	}
+	if len(paths) == 0 {
+		return []string{"."}
+	}
+	return paths
+}
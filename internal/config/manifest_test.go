@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"turbotilt/internal/render"
@@ -159,6 +160,66 @@ func TestValidateManifest(t *testing.T) {
     path: ./mysql`,
 			wantErrors: false,
 		},
+		{
+			name: "Unknown tool",
+			manifest: `tools:
+  - phpmyadmin
+services:
+  - name: test-app
+    path: ./app
+    runtime: spring`,
+			wantErrors: true,
+		},
+		{
+			name: "Seed of an undeclared database",
+			manifest: `seed:
+  database: mysql
+  scripts: [./seed/data.sql]
+services:
+  - name: test-app
+    path: ./app
+    runtime: spring
+  - name: db
+    path: ./db
+    type: postgres`,
+			wantErrors: true,
+		},
+		{
+			name: "Undeclared dependency",
+			manifest: `services:
+  - name: test-app
+    path: ./app
+    runtime: spring
+    dependencies: [redis]
+  - name: db
+    path: ./db
+    type: postgres`,
+			wantErrors: true,
+		},
+		{
+			name: "Unknown migration tool",
+			manifest: `seed:
+  tool: dbmate
+  migrations: ./db/migrations
+services:
+  - name: test-app
+    path: ./app
+    runtime: spring`,
+			wantErrors: true,
+		},
+		{
+			name: "Chaos on an undeclared service",
+			manifest: `chaos:
+  services: [redis]
+services:
+  - name: test-app
+    path: ./app
+    runtime: spring
+  - name: db
+    path: ./db
+    type: postgres`,
+			wantErrors: true,
+		},
 	}
 
 	// Create a temporary directory
@@ -196,3 +257,81 @@ func TestValidateManifest(t *testing.T) {
 		})
 	}
 }
+
+func TestApplicationDependencies(t *testing.T) {
+	root := t.TempDir()
+	orders := filepath.Join(root, "orders")
+	if err := os.MkdirAll(orders, 0755); err != nil {
+		t.Fatalf("Error creating directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(orders, "pom.xml"), []byte("<artifactId>postgresql</artifactId>"), 0644); err != nil {
+		t.Fatalf("Error writing file: %v", err)
+	}
+
+	manifest := Manifest{Services: []ManifestService{
+		// Detected in its directory
+		{Name: "orders", Path: orders, Runtime: "spring"},
+		// Declared, by name or alias
+		{Name: "catalog", Path: filepath.Join(root, "catalog"), Runtime: "quarkus", Dependencies: []string{"cache", "postgresql"}},
+		{Name: "db", Path: filepath.Join(root, "db"), Type: "postgres"},
+		{Name: "cache", Path: filepath.Join(root, "cache"), Type: "redis"},
+		// Used by no application
+		{Name: "mail", Path: filepath.Join(root, "mail"), Type: "mailpit"},
+	}}
+
+	dependencies := manifest.ApplicationDependencies()
+	types := func(name string) []string {
+		var list []string
+		for _, service := range dependencies[name] {
+			list = append(list, string(service.Type))
+		}
+		return list
+	}
+	if got := strings.Join(types("orders"), ","); got != "postgres,mailpit" {
+		t.Errorf("orders should use postgres and the unused mailpit, got %s", got)
+	}
+	if got := strings.Join(types("catalog"), ","); got != "postgres,redis,mailpit" {
+		t.Errorf("catalog should use its dependencies and the unused mailpit, got %s", got)
+	}
+}
+
+func TestSaveManifestSuggestingStubs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ManifestFileName)
+	manifest := Manifest{Services: []ManifestService{{Name: "api", Runtime: "spring", Path: "."}}}
+	if err := SaveManifestSuggestingStubs(manifest, path, []string{"api.stripe.com", "maps.example.com"}); err != nil {
+		t.Fatalf("SaveManifestSuggestingStubs returned an error: %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unable to read %s: %v", path, err)
+	}
+	for _, expected := range []string{"# stubs:", "#     - api.stripe.com", "#     - maps.example.com"} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("Manifest should suggest %q:\n%s", expected, content)
+		}
+	}
+
+	// The suggested stubs are commented out, no host is rerouted until the user opts in
+	saved, err := LoadManifest(path)
+	if err != nil {
+		t.Fatalf("LoadManifest returned an error: %v", err)
+	}
+	if saved.Stubs != nil {
+		t.Errorf("Stubs = %+v, want none", saved.Stubs)
+	}
+}
+
+func TestEnvironmentSections(t *testing.T) {
+	manifest := Manifest{Services: []ManifestService{{Name: "postgres", Type: "postgres"}}}
+	if sections := manifest.EnvironmentSections(); len(sections) != 0 {
+		t.Errorf("EnvironmentSections = %v, want none", sections)
+	}
+
+	manifest.Seed = &SeedConfig{Migrations: "db/migration"}
+	manifest.Tools = []string{"pgadmin"}
+	manifest.Stubs = &StubsConfig{}
+	if sections := manifest.EnvironmentSections(); strings.Join(sections, ",") != "tools,seed,stubs" {
+		t.Errorf("EnvironmentSections = %v, want tools, seed and stubs", sections)
+	}
+}
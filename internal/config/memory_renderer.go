@@ -3,12 +3,11 @@ package config
 import (
 	"fmt"
 	"turbotilt/internal/render"
-	"turbotilt/internal/scan"
 )
 
 // GenerateFilesFromMemory génère des fichiers Dockerfile, docker-compose.yml et Tiltfile
-// à partir de la configuration stockée en mémoire
-func GenerateFilesFromMemory() error {
+// à partir de la configuration stockée en mémoire, dans le dossier de sortie outputDir
+func GenerateFilesFromMemory(outputDir string) error {
 	store := GetMemoryStore()
 	if !store.HasSelectedServices() {
 		return fmt.Errorf("no services found in memory")
@@ -24,6 +23,7 @@ func GenerateFilesFromMemory() error {
 		if err != nil {
 			return fmt.Errorf("error converting service to render options: %w", err)
 		}
+		opts.OutputDir = outputDir
 
 		// Génération du Dockerfile
 		if err := render.GenerateDockerfile(*opts); err != nil {
@@ -46,26 +46,18 @@ func GenerateFilesFromMemory() error {
 	// Cas multi-services
 	// Convertir les services du Manifest en options de rendu
 	serviceList := render.ServiceList{
-		Services: []render.Options{},
+		Services:  []render.Options{},
+		OutputDir: outputDir,
 	}
 
 	// Identifier les services d'application vs les services dépendants
 	appServices := []ManifestService{}
-	depServices := []scan.ServiceConfig{}
+	depServices := manifest.ApplicationDependencies()
 
 	for _, service := range manifest.Services {
-		// Vérifier si c'est un service d'application (avec runtime) ou un service dépendant (avec type)
+		// Vérifier si c'est un service d'application (avec runtime)
 		if service.Runtime != "" {
 			appServices = append(appServices, service)
-		} else if service.Type != "" {
-			// Convertir en scan.ServiceConfig
-			depService := scan.ServiceConfig{
-				Type:        scan.ServiceType(service.Type),
-				Version:     service.Version,
-				Port:        service.Port,
-				Credentials: service.Env,
-			}
-			depServices = append(depServices, depService)
 		}
 	}
 
@@ -81,10 +73,12 @@ func GenerateFilesFromMemory() error {
 			return fmt.Errorf("error converting service %s to render options: %w", service.Name, err)
 		}
 
-		// Ajouter les services dépendants à chaque service d'application
-		opts.Services = depServices
+		// Ajouter les services dépendants utilisés par chaque service d'application
+		opts.Services = depServices[service.Name]
+		opts.OutputDir = outputDir
 		serviceList.Services = append(serviceList.Services, *opts)
 	}
+	render.AssignDebugPorts(serviceList.Services)
 
 	// Génération du Tiltfile multi-services
 	if err := render.GenerateMultiServiceTiltfile(serviceList); err != nil {
@@ -99,6 +93,7 @@ func GenerateFilesFromMemory() error {
 		if err != nil {
 			continue
 		}
+		opts.OutputDir = outputDir
 
 		// Génération du Dockerfile dans le dossier du service
 		if err := render.GenerateDockerfile(*opts); err != nil {
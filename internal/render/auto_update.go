@@ -2,7 +2,6 @@ package render
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"time"
 	"turbotilt/internal/logger"
@@ -15,13 +14,20 @@ type ConfigInterface interface {
 	// Add other methods as needed
 }
 
-// AutoUpdateWatcher watches a Tiltfile for changes and updates it automatically
+// AutoUpdateWatcher polls the inputs of the generated files and regenerates them when they change.
+// Changes are debounced: files are regenerated once the inputs stopped changing for a while.
 type AutoUpdateWatcher struct {
 	tiltfilePath    string
 	projectRoot     string
 	conf            ConfigInterface
 	lastCheckTime   time.Time
 	checkInterval   time.Duration
+	debounce        time.Duration
+	inputs          func() []string // Files to watch, evaluated at every check
+	regenerate      func() error    // Regenerates the files
+	lastFingerprint Fingerprint
+	pendingSince    time.Time        // Time of the last change not regenerated yet
+	now             func() time.Time // Clock of the debounce (replaced in tests)
 	isRunning       bool
 	stopChan        chan struct{}
 	updateTriggered chan bool
@@ -29,15 +35,30 @@ type AutoUpdateWatcher struct {
 
 // NewAutoUpdateWatcher creates a new watcher for auto-updating Tiltfiles
 func NewAutoUpdateWatcher(tiltfilePath string, conf ConfigInterface) *AutoUpdateWatcher {
-	return &AutoUpdateWatcher{
+	w := &AutoUpdateWatcher{
 		tiltfilePath:    tiltfilePath,
 		projectRoot:     filepath.Dir(tiltfilePath),
 		conf:            conf,
 		lastCheckTime:   time.Now(),
 		checkInterval:   5 * time.Second,
+		now:             time.Now,
 		stopChan:        make(chan struct{}),
 		updateTriggered: make(chan bool, 1),
 	}
+	w.inputs = w.projectInputs
+	w.regenerate = w.updateTiltfile
+	return w
+}
+
+// NewRegeneratingWatcher creates a watcher polling the files returned by inputs every second
+// and calling regenerate once they stopped changing for two seconds
+func NewRegeneratingWatcher(tiltfilePath string, inputs func() []string, regenerate func() error) *AutoUpdateWatcher {
+	w := NewAutoUpdateWatcher(tiltfilePath, nil)
+	w.checkInterval = time.Second
+	w.debounce = 2 * time.Second
+	w.inputs = inputs
+	w.regenerate = regenerate
+	return w
 }
 
 // Start begins the auto-update watcher
@@ -48,6 +69,7 @@ func (w *AutoUpdateWatcher) Start() error {
 
 	logger.Info("Starting auto-update watcher for %s", w.tiltfilePath)
 	w.isRunning = true
+	w.lastFingerprint = NewFingerprint(w.inputs(), nil)
 
 	go w.watchLoop()
 
@@ -104,75 +126,65 @@ func (w *AutoUpdateWatcher) watchLoop() {
 	}
 }
 
-// checkForChanges checks if source files have changed and updates the Tiltfile if needed
+// checkForChanges regenerates the files once the inputs changed and then stayed unchanged for the debounce delay
 func (w *AutoUpdateWatcher) checkForChanges() {
-	// Scan for file changes that would affect the Tiltfile
-	changed := w.detectFileChanges()
-
-	if changed {
-		logger.Info("Detected changes, updating Tiltfile...")
-		err := w.updateTiltfile()
-		if err != nil {
-			fmt.Printf("Failed to update Tiltfile: %v\n", err)
-		} else {
-			logger.Info("Tiltfile updated successfully")
-			// Notify any waiting goroutines
-			select {
-			case w.updateTriggered <- true:
-			default:
-			}
-		}
+	if w.detectFileChanges() {
+		w.pendingSince = w.now()
+		logger.Debug("Change detected, waiting %v for other changes", w.debounce)
 	}
-}
 
-// detectFileChanges checks if any source files or configuration have changed
-func (w *AutoUpdateWatcher) detectFileChanges() bool {
-	// Check if pom.xml, build.gradle, or docker-compose.yml have changed
-	sources := []string{
-		filepath.Join(w.projectRoot, "pom.xml"),
-		filepath.Join(w.projectRoot, "build.gradle"),
-		filepath.Join(w.projectRoot, "docker-compose.yml"),
-		filepath.Join(w.projectRoot, "Dockerfile"),
+	if w.pendingSince.IsZero() || w.now().Sub(w.pendingSince) < w.debounce {
+		return
 	}
+	w.pendingSince = time.Time{}
 
-	for _, src := range sources {
-		if fileChanged(src, w.lastCheckTime) {
-			w.lastCheckTime = time.Now()
-			return true
-		}
+	logger.Info("Detected changes, regenerating files...")
+	if err := w.regenerate(); err != nil {
+		fmt.Printf("Failed to regenerate files: %v\n", err)
+		return
 	}
+	logger.Info("Files regenerated successfully")
 
-	return false
-}
-
-// updateTiltfile regenerates the Tiltfile
-func (w *AutoUpdateWatcher) updateTiltfile() error {
-	// Read the existing Tiltfile template
-	templatePath := filepath.Join(w.projectRoot, "turbotilt", "Tiltfile.template")
-	templateContent, err := os.ReadFile(templatePath)
-	if err != nil {
-		return fmt.Errorf("failed to read template: %w", err)
+	// Notify any waiting goroutines
+	select {
+	case w.updateTriggered <- true:
+	default:
 	}
+}
 
-	// Generate new Tiltfile content (here we'd normally call the renderer)
-	content := string(templateContent)
+// detectFileChanges checks if the content of a watched file changed since the last check
+func (w *AutoUpdateWatcher) detectFileChanges() bool {
+	current := NewFingerprint(w.inputs(), nil)
+	changes := w.lastFingerprint.Changes(current)
+	w.lastFingerprint = current
+	w.lastCheckTime = time.Now()
 
-	// Write the updated content to the file
-	err = os.WriteFile(w.tiltfilePath, []byte(content), 0644)
-	if err != nil {
-		return fmt.Errorf("failed to write Tiltfile: %w", err)
+	for _, change := range changes {
+		logger.Debug("Changed: %s", change)
 	}
+	return len(changes) > 0
+}
 
-	return nil
+// projectInputs returns the build files of the project root
+func (w *AutoUpdateWatcher) projectInputs() []string {
+	return []string{
+		filepath.Join(w.projectRoot, "pom.xml"),
+		filepath.Join(w.projectRoot, "build.gradle"),
+		filepath.Join(w.projectRoot, "build.gradle.kts"),
+	}
 }
 
-// fileChanged checks if a file has been modified since the last check time
-func fileChanged(filepath string, since time.Time) bool {
-	info, err := os.Stat(filepath)
-	if err != nil {
-		// If the file doesn't exist or can't be accessed, consider it unchanged
-		return false
+// updateTiltfile regenerates the Tiltfile from the configuration of the watcher
+func (w *AutoUpdateWatcher) updateTiltfile() error {
+	opts := Options{OutputDir: w.projectRoot}
+	if w.conf != nil {
+		opts.ServiceName = w.conf.GetServiceName()
+		opts.AppName = w.conf.GetServiceName()
+		opts.Framework = w.conf.GetFramework()
 	}
 
-	return info.ModTime().After(since)
+	if err := GenerateTiltfile(opts); err != nil {
+		return fmt.Errorf("failed to write Tiltfile: %w", err)
+	}
+	return nil
 }
@@ -3,6 +3,7 @@ package render
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -132,3 +133,49 @@ func TestTriggerUpdate(t *testing.T) {
 		t.Error("TriggerUpdate() did not send a signal on the updateTriggered channel")
 	}
 }
+
+func TestRegeneratingWatcherDebounce(t *testing.T) {
+	tempDir := t.TempDir()
+	manifest := filepath.Join(tempDir, "turbotilt.yaml")
+	if err := os.WriteFile(manifest, []byte("services: []\n"), 0644); err != nil {
+		t.Fatalf("Unable to create manifest: %v", err)
+	}
+
+	regenerations := 0
+	watcher := NewRegeneratingWatcher(filepath.Join(tempDir, "Tiltfile"),
+		func() []string { return []string{manifest} },
+		func() error {
+			regenerations++
+			return nil
+		})
+
+	// The checks are run by hand against a fake clock instead of the polling loop
+	now := time.Unix(0, 0)
+	watcher.now = func() time.Time { return now }
+	watcher.lastFingerprint = NewFingerprint(watcher.inputs(), nil)
+
+	// Several quick changes result in a single regeneration
+	for i := 0; i < 3; i++ {
+		content := []byte("services:\n" + strings.Repeat("  - name: api\n", i+1))
+		if err := os.WriteFile(manifest, content, 0644); err != nil {
+			t.Fatalf("Unable to update manifest: %v", err)
+		}
+		watcher.checkForChanges()
+		now = now.Add(watcher.debounce / 2)
+	}
+	if regenerations != 0 {
+		t.Fatalf("The files should not be regenerated while the inputs change, got %d regenerations", regenerations)
+	}
+
+	now = now.Add(watcher.debounce)
+	watcher.checkForChanges()
+	watcher.checkForChanges()
+	if regenerations != 1 {
+		t.Errorf("Expected a single regeneration, got %d", regenerations)
+	}
+	select {
+	case <-watcher.updateTriggered:
+	default:
+		t.Error("The regeneration should be notified to the waiting goroutines")
+	}
+}
@@ -0,0 +1,162 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultChaosPort is the host port of the Toxiproxy API used when none is set
+	DefaultChaosPort = "8474"
+
+	// chaosServiceName is the compose service of Toxiproxy
+	chaosServiceName = "toxiproxy"
+
+	// chaosDir is the folder of the output directory receiving the configuration of Toxiproxy
+	chaosDir = "chaos"
+)
+
+// ChaosOptions configures Toxiproxy between the application services and some of their dependent
+// services, so that faults (latency, cut connections) can be injected through its API
+type ChaosOptions struct {
+	Enabled  bool
+	Services []string // Types of the dependent services proxied
+	Port     string   // Host port of the Toxiproxy API (DefaultChaosPort when empty)
+}
+
+// ChaosProxy is a proxy of Toxiproxy, named after the dependent service it forwards to
+type ChaosProxy struct {
+	Name     string `json:"name"`
+	Listen   string `json:"listen"`
+	Upstream string `json:"upstream"`
+	Enabled  bool   `json:"enabled"`
+}
+
+// chaosProxies returns the proxies of the dependent services of the chaos options, each one
+// listening on the port of its service unless another proxy already does
+func chaosProxies(serviceList ServiceList) ([]ChaosProxy, error) {
+	if !serviceList.Chaos.Enabled {
+		return nil, nil
+	}
+
+	catalog := defaultCatalog()
+	wanted := make(map[string]bool)
+	for _, name := range serviceList.Chaos.Services {
+		recipe, ok := catalog.Lookup(name)
+		if !ok {
+			return nil, fmt.Errorf("chaos: unknown service type '%s'", name)
+		}
+		if !recipe.proxiable() {
+			return nil, fmt.Errorf("chaos: the connection to %s cannot go through a proxy", recipe.Name)
+		}
+		wanted[recipe.Name] = true
+	}
+
+	var proxies []ChaosProxy
+	declared := make(map[string]bool)
+	listening := map[int]bool{8474: true} // Port of the API
+	for _, opts := range serviceList.Services {
+		for _, service := range opts.Services {
+			recipe, ok := catalog.Lookup(string(service.Type))
+			if !ok || !wanted[recipe.Name] || declared[recipe.Name] {
+				continue
+			}
+			declared[recipe.Name] = true
+
+			port, err := strconv.Atoi(recipe.Port)
+			if err != nil {
+				return nil, fmt.Errorf("chaos: invalid port '%s' of %s", recipe.Port, recipe.Name)
+			}
+			for listening[port] {
+				port++
+			}
+			listening[port] = true
+			proxies = append(proxies, ChaosProxy{
+				Name:     recipe.Name,
+				Listen:   fmt.Sprintf("0.0.0.0:%d", port),
+				Upstream: recipe.Name + ":" + recipe.Port,
+				Enabled:  true,
+			})
+		}
+	}
+	return proxies, nil
+}
+
+// proxyConnections connects an application service to its proxied dependent services through
+// Toxiproxy, unless its environment file sets the connection
+func proxyConnections(service *ComposeServiceDefinition, opts Options, proxies []ChaosProxy) {
+	catalog := defaultCatalog()
+	envFileKeys := readEnvFileKeys(getEnvFilePath(servicePathOf(opts)))
+	proxied := false
+	for _, dependency := range opts.Services {
+		recipe, ok := catalog.Lookup(string(dependency.Type))
+		if !ok {
+			continue
+		}
+		for _, proxy := range proxies {
+			if proxy.Name != recipe.Name {
+				continue
+			}
+			_, port, _ := strings.Cut(proxy.Listen, ":")
+			for key, value := range recipe.connectionEnvVia(opts.Framework, recipe.definition(dependency), chaosServiceName, port) {
+				if !envFileKeys[key] {
+					service.Environment[key] = value
+				}
+			}
+			proxied = true
+		}
+	}
+	if proxied {
+		service.DependsOn = append(service.DependsOn, chaosServiceName)
+	}
+}
+
+// chaosServiceDefinition returns the compose definition of Toxiproxy, loading its proxies from the
+// configuration of the chaos folder
+func chaosServiceDefinition(options ChaosOptions, proxies []ChaosProxy) ComposeServiceDefinition {
+	definition := ComposeServiceDefinition{
+		Name:    chaosServiceName,
+		Image:   "ghcr.io/shopify/toxiproxy:2.9.0",
+		Port:    getOrDefault(options.Port, DefaultChaosPort) + ":8474",
+		Command: "-host=0.0.0.0 -config=/config/toxiproxy.json",
+		Volumes: []string{"./" + chaosDir + "/toxiproxy.json:/config/toxiproxy.json:ro"},
+	}
+	for _, proxy := range proxies {
+		definition.DependsOn = append(definition.DependsOn, proxy.Name)
+	}
+	return definition
+}
+
+// generateChaosConfig writes the proxies loaded by Toxiproxy in the output directory
+func generateChaosConfig(serviceList ServiceList, proxies []ChaosProxy) error {
+	content, err := json.MarshalIndent(proxies, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Toxiproxy only reads plain JSON, the file cannot carry the header of the generated files
+	if err := ensureOutputDir(serviceList.OutputDir); err != nil {
+		return fmt.Errorf("error creating output directory: %w", err)
+	}
+	return writeGeneratedPlainFile(outputPath(serviceList.OutputDir, chaosDir, "toxiproxy.json"), append(content, '\n'), serviceList.Force)
+}
+
+// ChaosFiles returns the configuration files of Toxiproxy, relative to the output directory
+func ChaosFiles() []string {
+	return []string{chaosDir + "/toxiproxy.json"}
+}
+
+// chaosEndpoints returns the API of Toxiproxy when it is added to the environment
+func chaosEndpoints(serviceList ServiceList) []Endpoint {
+	if !serviceList.Chaos.Enabled {
+		return nil
+	}
+	return []Endpoint{{Name: chaosServiceName, Kind: "chaos", URL: ChaosURL(serviceList.Chaos)}}
+}
+
+// ChaosURL returns the address of the Toxiproxy API on the host
+func ChaosURL(options ChaosOptions) string {
+	return "http://localhost:" + getOrDefault(options.Port, DefaultChaosPort)
+}
@@ -0,0 +1,121 @@
+package render
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"turbotilt/internal/scan"
+)
+
+func TestChaos(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Unable to get current working directory: %v", err)
+	}
+	defer os.Chdir(oldWd)
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Unable to change directory: %v", err)
+	}
+	t.Setenv("HOME", tempDir)
+
+	newServiceList := func(chaos ChaosOptions) ServiceList {
+		return ServiceList{
+			OutputDir: ".turbotilt",
+			Force:     true,
+			Chaos:     chaos,
+			Services: []Options{{
+				ServiceName: "api",
+				Framework:   "spring",
+				Port:        "8080",
+				Path:        "api",
+				OutputDir:   ".turbotilt",
+				Services: []scan.ServiceConfig{
+					{Type: scan.Redis},
+					{Type: scan.PostgreSQL},
+					{Type: scan.MongoDB},
+				},
+			}},
+		}
+	}
+
+	// The connections to the proxied services go through Toxiproxy, the others are unchanged
+	serviceList := newServiceList(ChaosOptions{Enabled: true, Services: []string{"postgres", "redis"}})
+	if err := GenerateMultiServiceCompose(serviceList); err != nil {
+		t.Fatalf("GenerateMultiServiceCompose returned an error: %v", err)
+	}
+	compose, err := os.ReadFile(filepath.Join(".turbotilt", "docker-compose.yml"))
+	if err != nil {
+		t.Fatalf("Unable to read docker-compose.yml: %v", err)
+	}
+	for _, expected := range []string{
+		"- SPRING_DATASOURCE_URL=jdbc:postgresql://toxiproxy:5432/app",
+		"- SPRING_DATA_REDIS_HOST=toxiproxy",
+		"- SPRING_DATA_REDIS_PORT=6379",
+		"- SPRING_DATA_MONGODB_URI=mongodb://mongodb:27017/app",
+		"  toxiproxy:\n    image: ghcr.io/shopify/toxiproxy:2.9.0\n    command: -host=0.0.0.0 -config=/config/toxiproxy.json\n",
+		"- '8474:8474'",
+		"- ./chaos/toxiproxy.json:/config/toxiproxy.json:ro",
+	} {
+		if !strings.Contains(string(compose), expected) {
+			t.Errorf("docker-compose.yml should contain %q:\n%s", expected, compose)
+		}
+	}
+
+	content, err := os.ReadFile(filepath.Join(".turbotilt", "chaos", "toxiproxy.json"))
+	if err != nil {
+		t.Fatalf("Unable to read toxiproxy.json: %v", err)
+	}
+	var proxies []ChaosProxy
+	if err := json.Unmarshal(content, &proxies); err != nil {
+		t.Fatalf("toxiproxy.json is not valid JSON: %v", err)
+	}
+	expected := []ChaosProxy{
+		{Name: "redis", Listen: "0.0.0.0:6379", Upstream: "redis:6379", Enabled: true},
+		{Name: "postgres", Listen: "0.0.0.0:5432", Upstream: "postgres:5432", Enabled: true},
+	}
+	if len(proxies) != len(expected) {
+		t.Fatalf("Unexpected proxies: %+v", proxies)
+	}
+	for i := range expected {
+		if proxies[i] != expected[i] {
+			t.Errorf("Proxy %d should be %+v, got %+v", i, expected[i], proxies[i])
+		}
+	}
+
+	// The proxies are protected as the other generated files once edited
+	if err := os.WriteFile(filepath.Join(".turbotilt", "chaos", "toxiproxy.json"), []byte("[]\n"), 0644); err != nil {
+		t.Fatalf("Unable to edit toxiproxy.json: %v", err)
+	}
+	serviceList.Force = false
+	var overwriteErr *OverwriteError
+	if err := GenerateMultiServiceCompose(serviceList); !errors.As(err, &overwriteErr) || overwriteErr.Ownership != FileModified {
+		t.Errorf("Expected an OverwriteError on the edited proxies, got %v", err)
+	}
+	serviceList.Force = true
+	if err := GenerateMultiServiceCompose(serviceList); err != nil {
+		t.Fatalf("GenerateMultiServiceCompose with force returned an error: %v", err)
+	}
+
+	// The Tiltfile shows Toxiproxy
+	if err := GenerateMultiServiceTiltfile(serviceList); err != nil {
+		t.Fatalf("GenerateMultiServiceTiltfile returned an error: %v", err)
+	}
+	tiltfile, err := os.ReadFile(filepath.Join(".turbotilt", "Tiltfile"))
+	if err != nil {
+		t.Fatalf("Unable to read the Tiltfile: %v", err)
+	}
+	if !strings.Contains(string(tiltfile), "dc_resource('toxiproxy', labels=['chaos'], links=[link('http://localhost:8474/proxies', 'Toxiproxy')])") {
+		t.Errorf("The Tiltfile should declare Toxiproxy:\n%s", tiltfile)
+	}
+
+	// Kafka clients connect to the advertised brokers, a proxy would be bypassed
+	serviceList = newServiceList(ChaosOptions{Enabled: true, Services: []string{"kafka"}})
+	if err := GenerateMultiServiceCompose(serviceList); err == nil {
+		t.Error("GenerateMultiServiceCompose should reject the proxy of Kafka")
+	}
+}
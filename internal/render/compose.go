@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"turbotilt/internal/scan"
 )
@@ -11,202 +13,254 @@ import (
 // ComposeServiceDefinition defines a service in docker-compose.yml
 type ComposeServiceDefinition struct {
 	Name        string
-	Image       string
+	Image       string // Image reference (used when Build is empty)
+	Build       string // Build context
+	Dockerfile  string // Dockerfile relative to the build context, when not in the build context
 	Port        string
+	DebugPort   string   // Host port mapped to the JDWP agent
+	ExtraPorts  []string // Additional port mappings
 	Environment map[string]string
+	Command     string // Command overriding the one of the image
+	Labels      map[string]string
 	Volumes     []string
 	DependsOn   []string
+	Completed   []string // Services that must have completed successfully before the service starts
 	EnvFile     string
+	Watch       []ComposeWatchRule // develop.watch rules used by docker compose watch
+	Healthcheck *RecipeHealthcheck // Readiness of the service, awaited by the services depending on it
+	Script      string             // Shell script run instead of the command of the image
+	OneShot     bool               // Runs to completion, the services depending on it wait for its success
 }
 
 // GenerateComposeWithServices generates a docker-compose.yml including detected services
 func GenerateComposeWithServices(opts Options) error {
-	// Use services from the opts object
-	services := opts.Services
-	f, err := os.Create("docker-compose.yml")
-	if err != nil {
-		return fmt.Errorf("error creating docker-compose.yml: %w", err)
-	}
-	defer f.Close()
+	// Main application service
+	appService := appServiceDefinition(opts)
+	appService.Volumes = []string{fmt.Sprintf("%s/src:/app/src", serviceDirFromOutput(opts))}
+
+	// List of services to include in docker-compose.yml
+	serviceDefinitions := []ComposeServiceDefinition{appService}
+	volumes := make(map[string]bool)
+	declared := map[string]bool{appService.Name: true}
+
+	// Add detected services, declaring each of them only once
+	for _, service := range opts.Services {
+		definitions, serviceVolumes := dependencyServiceDefinitions(service, opts.OutputDir)
+		if len(definitions) == 0 || declared[definitions[0].Name] {
+			continue
+		}
 
-	// Determine the service path
-	servicePath := "."
-	if opts.Path != "" && opts.Path != "." {
-		servicePath = opts.Path
+		dependOn(&appService, definitions)
+		for _, definition := range definitions {
+			if !declared[definition.Name] {
+				declared[definition.Name] = true
+				serviceDefinitions = append(serviceDefinitions, definition)
+			}
+		}
+		for _, volume := range serviceVolumes {
+			volumes[volume] = true
+		}
 	}
 
-	// Main application service
+	// Update the application definition to add dependencies
+	serviceDefinitions[0] = appService
+
+	// Write the content to the file
+	content := renderComposeFile(composeProject(opts.OutputDir), serviceDefinitions, volumes)
+	return writeOutputFile(opts.OutputDir, "docker-compose.yml", []byte(content), opts.Force)
+}
+
+// appServiceDefinition builds the compose definition of an application service
+func appServiceDefinition(opts Options) ComposeServiceDefinition {
 	appName := "app"
 	if opts.ServiceName != "" {
 		appName = opts.ServiceName
 	}
 
+	servicePath := servicePathOf(opts)
 	appService := ComposeServiceDefinition{
-		Name:  appName,
-		Image: fmt.Sprintf("build: %s", servicePath),
-		Port:  fmt.Sprintf("%s:%s", opts.Port, opts.Port),
-		Volumes: []string{
-			fmt.Sprintf("%s/src:/app/src", servicePath),
-		},
+		Name:        appName,
+		Port:        fmt.Sprintf("%s:%s", opts.Port, opts.Port),
 		Environment: make(map[string]string),
 	}
 
+	// Images built by Jib or Buildpacks are referenced by name, the others are built by Compose
+	if UsesDockerfile(opts) {
+		appService.Build = serviceDirFromOutput(opts)
+		appService.Dockerfile = composeDockerfile(opts)
+		appService.Watch = composeWatchRules(opts)
+	} else {
+		appService.Image = imageName(opts)
+	}
+
 	// Check if an environment file exists
-	envFile := getEnvFilePath(servicePath)
-	if envFile != "" {
-		// Add the environment file to the service
-		appService.EnvFile = envFile
+	if envFile := getEnvFilePath(servicePath); envFile != "" {
+		appService.EnvFile = relativeToOutput(opts.OutputDir, envFile)
 	}
 
 	// Configure the environment according to the framework
+	profile := "prod"
+	if opts.DevMode {
+		profile = "dev"
+	}
 	switch opts.Framework {
 	case "spring":
-		if opts.DevMode {
-			appService.Environment["SPRING_PROFILES_ACTIVE"] = "dev"
-		} else {
-			appService.Environment["SPRING_PROFILES_ACTIVE"] = "prod"
-		}
+		appService.Environment["SPRING_PROFILES_ACTIVE"] = profile
 	case "quarkus":
-		if opts.DevMode {
-			appService.Environment["QUARKUS_PROFILE"] = "dev"
-		} else {
-			appService.Environment["QUARKUS_PROFILE"] = "prod"
-		}
+		appService.Environment["QUARKUS_PROFILE"] = profile
 	case "micronaut":
-		if opts.DevMode {
-			appService.Environment["MICRONAUT_ENVIRONMENTS"] = "dev"
-		} else {
-			appService.Environment["MICRONAUT_ENVIRONMENTS"] = "prod"
+		appService.Environment["MICRONAUT_ENVIRONMENTS"] = profile
+	}
+
+	// Connect the application to its dependent services, unless the environment file does
+	envFileKeys := readEnvFileKeys(getEnvFilePath(servicePath))
+	for key, value := range connectionEnvironment(opts) {
+		if !envFileKeys[key] {
+			appService.Environment[key] = value
 		}
 	}
 
-	// List of services to include in docker-compose.yml
-	serviceDefinitions := []ComposeServiceDefinition{appService}
-	volumes := make(map[string]bool)
+	// Start the JDWP agent for remote debugging
+	if debugPort := debugPortOf(opts); debugPort != "" {
+		appService.DebugPort = debugPort
+		appService.Environment["JAVA_TOOL_OPTIONS"] = jdwpOptions()
+	}
 
-	// Add detected services
-	for _, service := range services {
-		var serviceDefinition ComposeServiceDefinition
-
-		switch service.Type {
-		case scan.MySQL:
-			serviceDefinition = ComposeServiceDefinition{
-				Name:    "mysql",
-				Image:   fmt.Sprintf("image: mysql:%s", getOrDefault(service.Version, "latest")),
-				Port:    fmt.Sprintf("%s:3306", getOrDefault(service.Port, "3306")),
-				Volumes: []string{"mysql_data:/var/lib/mysql"},
-				Environment: map[string]string{
-					"MYSQL_ROOT_PASSWORD": getFromCredentials(service.Credentials, "MYSQL_ROOT_PASSWORD", "root"),
-					"MYSQL_DATABASE":      getFromCredentials(service.Credentials, "MYSQL_DATABASE", "app"),
-				},
-			}
-			volumes["mysql_data"] = true
-
-		case scan.PostgreSQL:
-			serviceDefinition = ComposeServiceDefinition{
-				Name:    "postgres",
-				Image:   fmt.Sprintf("image: postgres:%s", getOrDefault(service.Version, "latest")),
-				Port:    fmt.Sprintf("%s:5432", getOrDefault(service.Port, "5432")),
-				Volumes: []string{"postgres_data:/var/lib/postgresql/data"},
-				Environment: map[string]string{
-					"POSTGRES_USER":     getFromCredentials(service.Credentials, "POSTGRES_USER", "postgres"),
-					"POSTGRES_PASSWORD": getFromCredentials(service.Credentials, "POSTGRES_PASSWORD", "postgres"),
-					"POSTGRES_DB":       getFromCredentials(service.Credentials, "POSTGRES_DB", "app"),
-				},
-			}
-			volumes["postgres_data"] = true
-
-		case scan.MongoDB:
-			serviceDefinition = ComposeServiceDefinition{
-				Name:    "mongodb",
-				Image:   fmt.Sprintf("image: mongo:%s", getOrDefault(service.Version, "latest")),
-				Port:    fmt.Sprintf("%s:27017", getOrDefault(service.Port, "27017")),
-				Volumes: []string{"mongo_data:/data/db"},
-			}
-			volumes["mongo_data"] = true
-
-		case scan.Redis:
-			serviceDefinition = ComposeServiceDefinition{
-				Name:    "redis",
-				Image:   fmt.Sprintf("image: redis:%s", getOrDefault(service.Version, "latest")),
-				Port:    fmt.Sprintf("%s:6379", getOrDefault(service.Port, "6379")),
-				Volumes: []string{"redis_data:/data"},
-			}
-			volumes["redis_data"] = true
-
-		case scan.Kafka:
-			serviceDefinition = ComposeServiceDefinition{
-				Name:  "kafka",
-				Image: "image: confluentinc/cp-kafka:latest",
-				Port:  "9092:9092",
-				Environment: map[string]string{
-					"KAFKA_ADVERTISED_LISTENERS":             "PLAINTEXT://kafka:9092",
-					"KAFKA_LISTENER_SECURITY_PROTOCOL_MAP":   "PLAINTEXT:PLAINTEXT",
-					"KAFKA_INTER_BROKER_LISTENER_NAME":       "PLAINTEXT",
-					"KAFKA_OFFSETS_TOPIC_REPLICATION_FACTOR": "1",
-				},
-				DependsOn: []string{"zookeeper"},
-			}
+	return appService
+}
 
-			// Also add Zookeeper
-			zookeeper := ComposeServiceDefinition{
-				Name:  "zookeeper",
-				Image: "image: confluentinc/cp-zookeeper:latest",
-				Port:  "2181:2181",
-				Environment: map[string]string{
-					"ZOOKEEPER_CLIENT_PORT": "2181",
-				},
-			}
-			serviceDefinitions = append(serviceDefinitions, zookeeper)
-
-		case scan.RabbitMQ:
-			serviceDefinition = ComposeServiceDefinition{
-				Name:    "rabbitmq",
-				Image:   "image: rabbitmq:3-management",
-				Port:    "5672:5672",
-				Volumes: []string{"rabbitmq_data:/var/lib/rabbitmq"},
-				Environment: map[string]string{
-					"RABBITMQ_DEFAULT_USER": getFromCredentials(service.Credentials, "RABBITMQ_DEFAULT_USER", "guest"),
-					"RABBITMQ_DEFAULT_PASS": getFromCredentials(service.Credentials, "RABBITMQ_DEFAULT_PASS", "guest"),
-				},
-			}
-			volumes["rabbitmq_data"] = true
-
-		case scan.ElasticSearch:
-			serviceDefinition = ComposeServiceDefinition{
-				Name:    "elasticsearch",
-				Image:   "image: docker.elastic.co/elasticsearch/elasticsearch:7.14.0",
-				Port:    "9200:9200",
-				Volumes: []string{"es_data:/usr/share/elasticsearch/data"},
-				Environment: map[string]string{
-					"discovery.type": "single-node",
-					"ES_JAVA_OPTS":   "-Xms512m -Xmx512m",
-				},
-			}
-			volumes["es_data"] = true
+// dependencyServiceDefinitions returns the compose definitions and named volumes of a dependent service,
+// from its recipe. The first definition is the service itself, the following ones are its one-shot
+// initialization and the recipes it requires. Project files are mounted relative to the output directory.
+func dependencyServiceDefinitions(service scan.ServiceConfig, outputDir string) ([]ComposeServiceDefinition, []string) {
+	return recipeDefinitions(defaultCatalog(), service, outputDir)
+}
+
+// recipeDefinitions returns the compose definitions and named volumes of a service and of the recipes it requires
+func recipeDefinitions(catalog *RecipeCatalog, service scan.ServiceConfig, outputDir string) ([]ComposeServiceDefinition, []string) {
+	recipe, ok := catalog.Lookup(string(service.Type))
+	if !ok {
+		return nil, nil
+	}
+
+	definition := recipe.definition(service)
+	volumes := append([]string(nil), service.Volumes...)
+	mounts, _, _ := recipe.projectMounts(service, definition, false)
+	volumes = append(volumes, mounts...)
+	if recipe.SeedDir != "" {
+		volumes = append(volumes, seedMounts(service.Seeds, recipe.SeedDir)...)
+	}
+	for _, volume := range volumes {
+		definition.Volumes = append(definition.Volumes, mountFromOutput(outputDir, volume))
+	}
+	definitions := []ComposeServiceDefinition{definition}
+	if initialization, ok := recipe.initDefinition(service, definition); ok {
+		for i, volume := range initialization.Volumes {
+			initialization.Volumes[i] = mountFromOutput(outputDir, volume)
+		}
+		definitions = append(definitions, initialization)
+	}
+
+	namedVolumes := recipe.namedVolumes()
+	for _, required := range recipe.Requires {
+		if required == recipe.Name {
+			continue
 		}
+		requiredDefinitions, requiredVolumes := recipeDefinitions(catalog, scan.ServiceConfig{Type: scan.ServiceType(required)}, outputDir)
+		definitions = append(definitions, requiredDefinitions...)
+		namedVolumes = append(namedVolumes, requiredVolumes...)
+	}
+	return definitions, namedVolumes
+}
 
-		if serviceDefinition.Name != "" {
-			appService.DependsOn = append(appService.DependsOn, serviceDefinition.Name)
-			serviceDefinitions = append(serviceDefinitions, serviceDefinition)
+// dependOn makes an application service wait for a dependent service and for its one-shot initialization
+func dependOn(appService *ComposeServiceDefinition, definitions []ComposeServiceDefinition) {
+	if len(definitions) == 0 {
+		return
+	}
+	appService.DependsOn = append(appService.DependsOn, definitions[0].Name)
+	for _, definition := range definitions[1:] {
+		if definition.OneShot {
+			appService.Completed = append(appService.Completed, definition.Name)
 		}
 	}
+}
 
-	// Update the application definition to add dependencies
-	serviceDefinitions[0] = appService
+// mountFromOutput rewrites the source of a bind mount, relative to the current directory,
+// so that it is relative to the output directory. Named volumes are returned unchanged.
+func mountFromOutput(outputDir, volume string) string {
+	source, target, found := strings.Cut(volume, ":")
+	if !found || !strings.ContainsAny(source, "./") {
+		return volume
+	}
+	return filepath.ToSlash(relativeToOutput(outputDir, source)) + ":" + target
+}
+
+// connectionEnvironment returns the environment connecting an application service to its dependent
+// services, as defined by their recipes
+func connectionEnvironment(opts Options) map[string]string {
+	catalog := defaultCatalog()
+	env := make(map[string]string)
+	for _, service := range opts.Services {
+		recipe, ok := catalog.Lookup(string(service.Type))
+		if !ok {
+			continue
+		}
+		for key, value := range recipe.connectionEnv(opts.Framework, recipe.definition(service)) {
+			env[key] = value
+		}
+	}
+	return env
+}
 
-	// Build the docker-compose.yml file content
+// renderComposeFile builds the content of a docker-compose.yml file.
+// Environment variables and volumes are sorted so that the output is stable between runs.
+func renderComposeFile(project string, serviceDefinitions []ComposeServiceDefinition, volumes map[string]bool) string {
 	var sb strings.Builder
-	sb.WriteString("version: '3'\n\nservices:\n")
+	sb.WriteString("version: '3'\n")
+	if project != "" {
+		sb.WriteString(fmt.Sprintf("name: %s\n", project))
+	}
+	sb.WriteString("\nservices:\n")
+
+	// Services with a healthcheck are awaited until they are healthy
+	healthy := make(map[string]bool)
+	for _, service := range serviceDefinitions {
+		if service.Healthcheck != nil {
+			healthy[service.Name] = true
+		}
+	}
 
 	// Add all services
 	for _, service := range serviceDefinitions {
 		sb.WriteString(fmt.Sprintf("  %s:\n", service.Name))
-		sb.WriteString(fmt.Sprintf("    %s\n", service.Image))
+		if service.Build != "" && service.Dockerfile != "" {
+			sb.WriteString("    build:\n")
+			sb.WriteString(fmt.Sprintf("      context: %s\n", service.Build))
+			sb.WriteString(fmt.Sprintf("      dockerfile: %s\n", service.Dockerfile))
+		} else if service.Build != "" {
+			sb.WriteString(fmt.Sprintf("    build: %s\n", service.Build))
+		} else {
+			sb.WriteString(fmt.Sprintf("    image: %s\n", service.Image))
+		}
+
+		if service.Script != "" {
+			sb.WriteString("    entrypoint: [\"/bin/sh\", \"-c\"]\n")
+			// The variables of the script are the ones of the shell, not interpolated by compose
+			sb.WriteString(fmt.Sprintf("    command: [%s]\n", strconv.Quote(strings.ReplaceAll(service.Script, "$", "$$"))))
+		} else if service.Command != "" {
+			sb.WriteString(fmt.Sprintf("    command: %s\n", service.Command))
+		}
 
 		if service.Port != "" {
 			sb.WriteString("    ports:\n")
 			sb.WriteString(fmt.Sprintf("      - '%s'\n", service.Port))
+			if service.DebugPort != "" {
+				sb.WriteString(fmt.Sprintf("      - '%s:%s'\n", service.DebugPort, containerDebugPort))
+			}
+			for _, port := range service.ExtraPorts {
+				sb.WriteString(fmt.Sprintf("      - '%s'\n", port))
+			}
 		}
 
 		// Add environment file if present
@@ -217,8 +271,15 @@ func GenerateComposeWithServices(opts Options) error {
 
 		if len(service.Environment) > 0 {
 			sb.WriteString("    environment:\n")
-			for k, v := range service.Environment {
-				sb.WriteString(fmt.Sprintf("      - %s=%s\n", k, v))
+			for _, k := range sortedKeys(service.Environment) {
+				sb.WriteString(fmt.Sprintf("      - %s=%s\n", k, service.Environment[k]))
+			}
+		}
+
+		if len(service.Labels) > 0 {
+			sb.WriteString("    labels:\n")
+			for _, k := range sortedKeys(service.Labels) {
+				sb.WriteString(fmt.Sprintf("      - '%s=%s'\n", k, service.Labels[k]))
 			}
 		}
 
@@ -229,27 +290,93 @@ func GenerateComposeWithServices(opts Options) error {
 			}
 		}
 
-		if len(service.DependsOn) > 0 {
+		if len(service.Completed) > 0 || dependsOnHealthy(service, healthy) {
+			// The long syntax is needed to wait for one-shot and healthy services
+			sb.WriteString("    depends_on:\n")
+			for _, dep := range service.DependsOn {
+				condition := "service_started"
+				if healthy[dep] {
+					condition = "service_healthy"
+				}
+				sb.WriteString(fmt.Sprintf("      %s:\n        condition: %s\n", dep, condition))
+			}
+			for _, dep := range service.Completed {
+				sb.WriteString(fmt.Sprintf("      %s:\n        condition: service_completed_successfully\n", dep))
+			}
+		} else if len(service.DependsOn) > 0 {
 			sb.WriteString("    depends_on:\n")
 			for _, dep := range service.DependsOn {
 				sb.WriteString(fmt.Sprintf("      - %s\n", dep))
 			}
 		}
 
+		if service.Healthcheck != nil {
+			sb.WriteString("    healthcheck:\n")
+			sb.WriteString(fmt.Sprintf("      test: %s\n", strconv.Quote(service.Healthcheck.Test)))
+			if service.Healthcheck.Interval != "" {
+				sb.WriteString(fmt.Sprintf("      interval: %s\n", service.Healthcheck.Interval))
+			}
+			if service.Healthcheck.Timeout != "" {
+				sb.WriteString(fmt.Sprintf("      timeout: %s\n", service.Healthcheck.Timeout))
+			}
+			if service.Healthcheck.Retries > 0 {
+				sb.WriteString(fmt.Sprintf("      retries: %d\n", service.Healthcheck.Retries))
+			}
+		}
+
+		if len(service.Watch) > 0 {
+			sb.WriteString("    develop:\n")
+			sb.WriteString("      watch:\n")
+			for _, rule := range service.Watch {
+				sb.WriteString(fmt.Sprintf("        - action: %s\n", rule.Action))
+				sb.WriteString(fmt.Sprintf("          path: %s\n", rule.Path))
+				if rule.Target != "" {
+					sb.WriteString(fmt.Sprintf("          target: %s\n", rule.Target))
+				}
+			}
+		}
+
 		sb.WriteString("\n")
 	}
 
 	// Add volumes if needed
 	if len(volumes) > 0 {
 		sb.WriteString("volumes:\n")
-		for volume := range volumes {
+		for _, volume := range sortedKeys(volumes) {
 			sb.WriteString(fmt.Sprintf("  %s:\n", volume))
 		}
 	}
 
-	// Write the content to the file
-	_, err = f.WriteString(sb.String())
-	return err
+	return sb.String()
+}
+
+// dependsOnHealthy checks if a service depends on a service with a healthcheck
+func dependsOnHealthy(service ComposeServiceDefinition, healthy map[string]bool) bool {
+	for _, dep := range service.DependsOn {
+		if healthy[dep] {
+			return true
+		}
+	}
+	return false
+}
+
+// composeProject returns the project name written in docker-compose.yml, only needed
+// when the file is generated in an output directory
+func composeProject(outputDir string) string {
+	if outputDir == "" || filepath.Clean(outputDir) == "." {
+		return ""
+	}
+	return composeProjectName()
+}
+
+// sortedKeys returns the keys of a map in alphabetical order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
 }
 
 // getOrDefault returns the value or a default value if empty
@@ -260,12 +387,27 @@ func getOrDefault(value, defaultValue string) string {
 	return value
 }
 
-// getFromCredentials retrieves a value from credentials or returns the default value
-func getFromCredentials(credentials map[string]string, key, defaultValue string) string {
-	if value, ok := credentials[key]; ok && value != "" {
-		return value
+// readEnvFileKeys returns the variables set by an environment file
+func readEnvFileKeys(path string) map[string]bool {
+	keys := make(map[string]bool)
+	if path == "" {
+		return keys
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return keys
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if key, _, found := strings.Cut(strings.TrimPrefix(line, "export "), "="); found {
+			keys[strings.TrimSpace(key)] = true
+		}
 	}
-	return defaultValue
+	return keys
 }
 
 // getEnvFilePath checks if an environment file exists for a service
@@ -283,173 +425,138 @@ func getEnvFilePath(servicePath string) string {
 
 // GenerateMultiServiceCompose generates a docker-compose.yml for a multi-service project declared in the manifest
 func GenerateMultiServiceCompose(serviceList ServiceList) error {
-	f, err := os.Create("docker-compose.yml")
+	serviceDefinitions := []ComposeServiceDefinition{}
+	volumes := make(map[string]bool)
+	declared := make(map[string]bool)
+
+	// The migrations and the seed scripts run before the application services
+	seedServices, err := seedDefinitions(serviceList)
 	if err != nil {
-		return fmt.Errorf("error creating docker-compose.yml: %w", err)
+		return err
 	}
-	defer f.Close()
 
-	// Build the docker-compose.yml file content
-	var sb strings.Builder
-	sb.WriteString("version: '3'\n\nservices:\n")
+	// Toxiproxy forwards the connections to the dependent services selected for fault injection
+	proxies, err := chaosProxies(serviceList)
+	if err != nil {
+		return err
+	}
 
 	// Add all application services
+	var stubs []scan.ExternalURL
 	for _, opts := range serviceList.Services {
 		// Ignore non-application services (without runtime)
 		if opts.Framework == "" {
 			continue
 		}
 
-		appName := opts.ServiceName
-		if appName == "" {
-			appName = "app"
+		appService := appServiceDefinition(opts)
+		appService.Volumes = []string{fmt.Sprintf("'%s:/app'", serviceDirFromOutput(opts))}
+		for _, service := range opts.Services {
+			definitions, _ := dependencyServiceDefinitions(service, opts.OutputDir)
+			dependOn(&appService, definitions)
 		}
-
-		// Determine the service path
-		servicePath := "."
-		if opts.Path != "" {
-			servicePath = opts.Path
+		for _, seed := range seedServices {
+			appService.Completed = append(appService.Completed, seed.Name)
 		}
-
-		// Add the service
-		sb.WriteString(fmt.Sprintf("  %s:\n", appName))
-		sb.WriteString(fmt.Sprintf("    build: %s\n", servicePath))
-		sb.WriteString("    ports:\n")
-		sb.WriteString(fmt.Sprintf("      - '%s:%s'\n", opts.Port, opts.Port))
-		sb.WriteString("    volumes:\n")
-		sb.WriteString(fmt.Sprintf("      - '%s:/app'\n", servicePath))
-
-		// Check if an environment file exists
-		envFile := getEnvFilePath(servicePath)
-		if envFile != "" {
-			sb.WriteString("    env_file:\n")
-			sb.WriteString(fmt.Sprintf("      - %s\n", envFile))
+		if len(proxies) > 0 {
+			proxyConnections(&appService, opts, proxies)
 		}
-
-		// Environment variables according to the framework
-		sb.WriteString("    environment:\n")
-		switch opts.Framework {
-		case "spring":
-			envValue := "prod"
-			if opts.DevMode {
-				envValue = "dev"
-			}
-			sb.WriteString(fmt.Sprintf("      - SPRING_PROFILES_ACTIVE=%s\n", envValue))
-		case "quarkus":
-			envValue := "prod"
-			if opts.DevMode {
-				envValue = "dev"
-			}
-			sb.WriteString(fmt.Sprintf("      - QUARKUS_PROFILE=%s\n", envValue))
-		case "micronaut":
-			envValue := "prod"
-			if opts.DevMode {
-				envValue = "dev"
-			}
-			sb.WriteString(fmt.Sprintf("      - MICRONAUT_ENVIRONMENTS=%s\n", envValue))
-		default:
-			sb.WriteString("      # Add your specific environment variables here\n")
+		if urls := stubbedURLs(opts, serviceList.Stubs); len(urls) > 0 {
+			stubURLs(&appService, opts, urls)
+			stubs = append(stubs, urls...)
 		}
-
-		sb.WriteString("\n")
+		if serviceList.Observability.Enabled {
+			instrumentService(&appService, opts)
+		}
+		if serviceList.Gateway.Enabled {
+			routeService(&appService, opts, serviceList)
+		} else if serviceList.TLS.Enabled {
+			serveOverTLS(&appService, opts)
+		}
+		serviceDefinitions = append(serviceDefinitions, appService)
+		declared[appService.Name] = true
 	}
 
-	// Add dependent services (MySQL, Redis, etc.) detected
-	volumes := make(map[string]bool)
-
-	// Go through each service for its dependencies
+	// Add dependent services (MySQL, Redis, etc.) detected, declaring each of them only once
 	for _, opts := range serviceList.Services {
-		// Process dependent services for this service
 		for _, service := range opts.Services {
-			var serviceDefinition ComposeServiceDefinition
-
-			switch service.Type {
-			case scan.MySQL:
-				serviceDefinition = ComposeServiceDefinition{
-					Name:    "mysql",
-					Image:   fmt.Sprintf("image: mysql:%s", getOrDefault(service.Version, "latest")),
-					Port:    fmt.Sprintf("%s:3306", getOrDefault(service.Port, "3306")),
-					Volumes: []string{"mysql_data:/var/lib/mysql"},
-					Environment: map[string]string{
-						"MYSQL_ROOT_PASSWORD": getFromCredentials(service.Credentials, "MYSQL_ROOT_PASSWORD", "root"),
-						"MYSQL_DATABASE":      getFromCredentials(service.Credentials, "MYSQL_DATABASE", "app"),
-					},
-				}
-				volumes["mysql_data"] = true
-
-			case scan.PostgreSQL:
-				serviceDefinition = ComposeServiceDefinition{
-					Name:    "postgres",
-					Image:   fmt.Sprintf("image: postgres:%s", getOrDefault(service.Version, "latest")),
-					Port:    fmt.Sprintf("%s:5432", getOrDefault(service.Port, "5432")),
-					Volumes: []string{"postgres_data:/var/lib/postgresql/data"},
-					Environment: map[string]string{
-						"POSTGRES_USER":     getFromCredentials(service.Credentials, "POSTGRES_USER", "postgres"),
-						"POSTGRES_PASSWORD": getFromCredentials(service.Credentials, "POSTGRES_PASSWORD", "postgres"),
-						"POSTGRES_DB":       getFromCredentials(service.Credentials, "POSTGRES_DB", "app"),
-					},
-				}
-				volumes["postgres_data"] = true
-
-			case scan.MongoDB:
-				serviceDefinition = ComposeServiceDefinition{
-					Name:    "mongodb",
-					Image:   fmt.Sprintf("image: mongo:%s", getOrDefault(service.Version, "latest")),
-					Port:    fmt.Sprintf("%s:27017", getOrDefault(service.Port, "27017")),
-					Volumes: []string{"mongo_data:/data/db"},
+			definitions, serviceVolumes := dependencyServiceDefinitions(service, serviceList.OutputDir)
+			for _, definition := range definitions {
+				if declared[definition.Name] {
+					continue
 				}
-				volumes["mongo_data"] = true
-
-			case scan.Redis:
-				serviceDefinition = ComposeServiceDefinition{
-					Name:    "redis",
-					Image:   fmt.Sprintf("image: redis:%s", getOrDefault(service.Version, "latest")),
-					Port:    fmt.Sprintf("%s:6379", getOrDefault(service.Port, "6379")),
-					Volumes: []string{"redis_data:/data"},
-				}
-				volumes["redis_data"] = true
+				declared[definition.Name] = true
+				serviceDefinitions = append(serviceDefinitions, definition)
 			}
+			for _, volume := range serviceVolumes {
+				volumes[volume] = true
+			}
+		}
+	}
 
-			if serviceDefinition.Name != "" {
-				// Add the service to docker-compose
-				sb.WriteString(fmt.Sprintf("  %s:\n", serviceDefinition.Name))
-				sb.WriteString(fmt.Sprintf("    %s\n", serviceDefinition.Image))
-
-				if serviceDefinition.Port != "" {
-					sb.WriteString("    ports:\n")
-					sb.WriteString(fmt.Sprintf("      - '%s'\n", serviceDefinition.Port))
-				}
+	for _, seed := range seedServices {
+		if !declared[seed.Name] {
+			declared[seed.Name] = true
+			serviceDefinitions = append(serviceDefinitions, seed)
+		}
+	}
 
-				if len(serviceDefinition.Environment) > 0 {
-					sb.WriteString("    environment:\n")
-					for k, v := range serviceDefinition.Environment {
-						sb.WriteString(fmt.Sprintf("      - %s=%s\n", k, v))
-					}
-				}
+	// WireMock replaces the third-party APIs called by the application services
+	for _, definition := range stubServiceDefinitions(stubs, serviceList.Stubs, serviceList.OutputDir) {
+		if !declared[definition.Name] {
+			declared[definition.Name] = true
+			serviceDefinitions = append(serviceDefinitions, definition)
+		}
+	}
 
-				if len(serviceDefinition.Volumes) > 0 {
-					sb.WriteString("    volumes:\n")
-					for _, volume := range serviceDefinition.Volumes {
-						sb.WriteString(fmt.Sprintf("      - %s\n", volume))
-					}
-				}
+	if len(proxies) > 0 && !declared[chaosServiceName] {
+		declared[chaosServiceName] = true
+		serviceDefinitions = append(serviceDefinitions, chaosServiceDefinition(serviceList.Chaos, proxies))
+		if err := generateChaosConfig(serviceList, proxies); err != nil {
+			return err
+		}
+	}
 
-				sb.WriteString("\n")
+	// Add the gateway routing <service>.localhost to the application services
+	if serviceList.Gateway.Enabled && !declared["gateway"] {
+		declared["gateway"] = true
+		gateway := gatewayServiceDefinition(serviceList.Gateway)
+		if serviceList.TLS.Enabled {
+			terminateTLS(&gateway, serviceList.Gateway)
+			if err := generateGatewayTLSConfig(serviceList); err != nil {
+				return err
 			}
 		}
+		serviceDefinitions = append(serviceDefinitions, gateway)
 	}
 
-	// Add volumes if needed
-	if len(volumes) > 0 {
-		sb.WriteString("volumes:\n")
-		for volume := range volumes {
-			sb.WriteString(fmt.Sprintf("  %s:\n", volume))
+	// Add the observability stack (collector, Jaeger, Prometheus, Grafana) and its configuration
+	if serviceList.Observability.Enabled {
+		definitions, stackVolumes := observabilityServiceDefinitions(serviceList.Observability)
+		for _, definition := range definitions {
+			if !declared[definition.Name] {
+				declared[definition.Name] = true
+				serviceDefinitions = append(serviceDefinitions, definition)
+			}
+		}
+		for _, volume := range stackVolumes {
+			volumes[volume] = true
 		}
+
+		if err := generateObservabilityConfig(serviceList); err != nil {
+			return err
+		}
+	}
+
+	// Add the administration tools connected to the dependent services
+	serviceDefinitions, err = addTools(serviceDefinitions, serviceList.Tools, serviceList.OutputDir, serviceList.Force)
+	if err != nil {
+		return err
 	}
 
 	// Write the content to the file
-	_, err = f.WriteString(sb.String())
-	return err
+	content := renderComposeFile(composeProject(serviceList.OutputDir), serviceDefinitions, volumes)
+	return writeOutputFile(serviceList.OutputDir, "docker-compose.yml", []byte(content), serviceList.Force)
 }
 
 // GenerateComposeMultiService is an alias for GenerateMultiServiceCompose
@@ -4,13 +4,19 @@ package render
 const (
 	// ComposeTemplateWithEnvFile is the docker-compose.yml template with an environment file
 	ComposeTemplateWithEnvFile = `version: '3'
-services:
-  app:
-    build: .
-    ports:
+{{if .Project}}name: {{.Project}}
+{{end}}services:
+  {{.ServiceName}}:
+{{if .Image}}    image: {{.Image}}
+{{else if .Dockerfile}}    build:
+      context: {{.BuildContext}}
+      dockerfile: {{.Dockerfile}}
+{{else}}    build: {{.BuildContext}}
+{{end}}    ports:
       - '{{.Port}}:{{.Port}}'
-    volumes:
-      - './src:/app/src'
+{{if .DebugPort}}      - '{{.DebugPort}}:5005'
+{{end}}    volumes:
+      - '{{.SourceDir}}:/app/src'
     env_file:
       - {{.EnvFile}}
     environment:
@@ -18,8 +24,14 @@ services:
 {{else if eq .Framework "quarkus"}}      - QUARKUS_PROFILE={{if .DevMode}}dev{{else}}prod{{end}}
 {{else if eq .Framework "micronaut"}}      - MICRONAUT_ENVIRONMENTS={{if .DevMode}}dev{{else}}prod{{end}}
 {{else}}      # Ajoutez vos variables d'environnement spécifiques ici
-{{end}}
-{{range .Services}}
+{{end}}{{if .DebugOptions}}      - JAVA_TOOL_OPTIONS={{.DebugOptions}}
+{{end}}{{if .Watch}}    develop:
+      watch:
+{{range .Watch}}        - action: {{.Action}}
+          path: {{.Path}}
+{{if .Target}}          target: {{.Target}}
+{{end}}{{end}}{{end}}
+{{range .Dependencies}}
   {{.Name}}:
     image: {{.Image}}
     ports:
@@ -30,20 +42,32 @@ services:
 
 	// ComposeTemplate is the docker-compose.yml template without an environment file
 	ComposeTemplate = `version: '3'
-services:
-  app:
-    build: .
-    ports:
+{{if .Project}}name: {{.Project}}
+{{end}}services:
+  {{.ServiceName}}:
+{{if .Image}}    image: {{.Image}}
+{{else if .Dockerfile}}    build:
+      context: {{.BuildContext}}
+      dockerfile: {{.Dockerfile}}
+{{else}}    build: {{.BuildContext}}
+{{end}}    ports:
       - '{{.Port}}:{{.Port}}'
-    volumes:
-      - './src:/app/src'
+{{if .DebugPort}}      - '{{.DebugPort}}:5005'
+{{end}}    volumes:
+      - '{{.SourceDir}}:/app/src'
     environment:
 {{if eq .Framework "spring"}}      - SPRING_PROFILES_ACTIVE={{if .DevMode}}dev{{else}}prod{{end}}
 {{else if eq .Framework "quarkus"}}      - QUARKUS_PROFILE={{if .DevMode}}dev{{else}}prod{{end}}
 {{else if eq .Framework "micronaut"}}      - MICRONAUT_ENVIRONMENTS={{if .DevMode}}dev{{else}}prod{{end}}
 {{else}}      # Ajoutez vos variables d'environnement spécifiques ici
-{{end}}
-{{range .Services}}
+{{end}}{{if .DebugOptions}}      - JAVA_TOOL_OPTIONS={{.DebugOptions}}
+{{end}}{{if .Watch}}    develop:
+      watch:
+{{range .Watch}}        - action: {{.Action}}
+          path: {{.Path}}
+{{if .Target}}          target: {{.Target}}
+{{end}}{{end}}{{end}}
+{{range .Dependencies}}
   {{.Name}}:
     image: {{.Image}}
     ports:
@@ -158,3 +158,147 @@ func TestGenerateComposeMultiService(t *testing.T) {
 		t.Error("The generated docker-compose.yml does not contain the 'postgres' service")
 	}
 }
+
+func TestGenerateComposeDuplicateServices(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Unable to get current working directory: %v", err)
+	}
+	defer os.Chdir(oldWd)
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Unable to change directory: %v", err)
+	}
+
+	// A service detected twice (driver and connection settings) is declared once
+	opts := Options{
+		Framework:   "spring",
+		ServiceName: "api",
+		Port:        "8080",
+		Path:        ".",
+		Services: []scan.ServiceConfig{
+			{Type: scan.PostgreSQL, Version: "16"},
+			{Type: scan.PostgreSQL},
+		},
+	}
+	if err := GenerateComposeWithServices(opts); err != nil {
+		t.Fatalf("GenerateComposeWithServices returned an error: %v", err)
+	}
+
+	content, err := os.ReadFile("docker-compose.yml")
+	if err != nil {
+		t.Fatalf("Unable to read the generated docker-compose.yml: %v", err)
+	}
+	if count := strings.Count(string(content), "\n  postgres:\n"); count != 1 {
+		t.Errorf("postgres should be declared once, got %d:\n%s", count, content)
+	}
+	if count := strings.Count(string(content), "      postgres:\n"); count != 1 {
+		t.Errorf("api should depend on postgres once, got %d:\n%s", count, content)
+	}
+	if !strings.Contains(string(content), "image: postgres:16") {
+		t.Errorf("The first declaration of postgres should be kept:\n%s", content)
+	}
+}
+
+func TestComposeWatchRules(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Unable to get current working directory: %v", err)
+	}
+	defer os.Chdir(oldWd)
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Unable to change directory: %v", err)
+	}
+
+	for _, dir := range []string{"api/src/main/java", "api/src/main/resources", "api/config"} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Unable to create %s: %v", dir, err)
+		}
+	}
+	if err := os.WriteFile("api/pom.xml", []byte("<project/>"), 0644); err != nil {
+		t.Fatalf("Unable to write pom.xml: %v", err)
+	}
+
+	serviceList := ServiceList{
+		OutputDir: ".turbotilt",
+		Services: []Options{{
+			ServiceName: "api",
+			Framework:   "spring",
+			Port:        "8080",
+			Path:        "api",
+			BuildSystem: "maven",
+			WatchPaths:  []string{"config"},
+			OutputDir:   ".turbotilt",
+		}},
+	}
+	if err := GenerateMultiServiceCompose(serviceList); err != nil {
+		t.Fatalf("GenerateMultiServiceCompose returned an error: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(".turbotilt", "docker-compose.yml"))
+	if err != nil {
+		t.Fatalf("Unable to read the generated docker-compose.yml: %v", err)
+	}
+
+	expected := `    develop:
+      watch:
+        - action: sync
+          path: ../api/src/main/java
+          target: /app/src/main/java
+        - action: sync+restart
+          path: ../api/src/main/resources
+          target: /app/src/main/resources
+        - action: rebuild
+          path: ../api/pom.xml
+        - action: sync
+          path: ../api/config
+          target: /app/config
+`
+	if !strings.Contains(string(content), expected) {
+		t.Errorf("Unexpected develop.watch section:\n%s", content)
+	}
+
+	// The layout of an existing Dockerfile is unknown, its image is rebuilt instead of synced
+	serviceList.Services[0].BuildStrategy = scan.BuildExistingDockerfile
+	var rules []string
+	for _, rule := range composeWatchRules(serviceList.Services[0]) {
+		rules = append(rules, rule.Action+" "+rule.Path+" "+rule.Target)
+	}
+	if want := []string{"rebuild ../api/src ", "rebuild ../api/pom.xml ", "rebuild ../api/config "}; strings.Join(rules, ",") != strings.Join(want, ",") {
+		t.Errorf("Watch rules of an existing Dockerfile = %q, want %q", rules, want)
+	}
+
+	// Images built by Jib are not built by Compose and cannot be watched
+	serviceList.Services[0].BuildStrategy = scan.BuildJib
+	if rules := composeWatchRules(serviceList.Services[0]); len(rules) != 0 {
+		t.Errorf("No watch rule expected for a Jib image, got %v", rules)
+	}
+}
+
+func TestComposeDebugPort(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Unable to get current working directory: %v", err)
+	}
+	defer os.Chdir(oldWd)
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Unable to change directory: %v", err)
+	}
+
+	opts := Options{ServiceName: "api", Framework: "spring", Port: "8080", Path: ".", Debug: true, DebugPort: "5006"}
+	if err := GenerateCompose(opts); err != nil {
+		t.Fatalf("GenerateCompose returned an error: %v", err)
+	}
+
+	content, err := os.ReadFile("docker-compose.yml")
+	if err != nil {
+		t.Fatalf("Unable to read the generated docker-compose.yml: %v", err)
+	}
+	for _, expected := range []string{"- '5006:5005'", "- JAVA_TOOL_OPTIONS=" + jdwpOptions()} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("docker-compose.yml should contain %q:\n%s", expected, content)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package render
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// ComposeWatchRule is an entry of the develop.watch section of a compose service,
+// used by "docker compose watch" to update the container without Tilt
+type ComposeWatchRule struct {
+	Action string // sync, sync+restart or rebuild
+	Path   string // Watched path, relative to the compose file
+	Target string // Path in the container (sync actions only)
+}
+
+// composeWatchRules returns the develop.watch rules of an application service: the framework
+// defaults mirroring the Tiltfile live_update, then the watchPaths of the manifest. The layout of an
+// existing Dockerfile is unknown, its image is rebuilt instead of synced. Images not built by Compose
+// (Jib, Buildpacks) cannot be watched, Compose only rebuilds the services it builds.
+func composeWatchRules(opts Options) []ComposeWatchRule {
+	if !UsesDockerfile(opts) {
+		return nil
+	}
+
+	servicePath := servicePathOf(opts)
+	serviceDir := serviceDirFromOutput(opts)
+
+	var rules []ComposeWatchRule
+	seen := make(map[string]bool)
+	add := func(action, source, target string, required bool) {
+		if seen[source] {
+			return
+		}
+		if required {
+			if _, err := os.Stat(filepath.Join(servicePath, filepath.FromSlash(source))); err != nil {
+				return
+			}
+		}
+		seen[source] = true
+		rules = append(rules, ComposeWatchRule{
+			Action: action,
+			Path:   path.Join(serviceDir, source),
+			Target: target,
+		})
+	}
+
+	// Files are only synced to the /app layout of the generated Dockerfile
+	sync := UsesGeneratedDockerfile(opts)
+	if !sync {
+		add("rebuild", "src", "", true)
+	} else {
+		switch opts.Framework {
+		case "spring", "micronaut":
+			add("sync", "src/main/java", "/app/src/main/java", true)
+			// Configuration changes need a restart
+			add("sync+restart", "src/main/resources", "/app/src/main/resources", true)
+		case "quarkus":
+			// Quarkus dev mode reloads both code and configuration
+			add("sync", "src/main/java", "/app/src/main/java", true)
+			add("sync", "src/main/resources", "/app/src/main/resources", true)
+		default:
+			add("sync", "src", "/app/src", true)
+		}
+	}
+
+	// Dependency changes need a new image
+	if resolveBuildSystem(opts) == "gradle" {
+		for _, file := range gradleBuildFiles {
+			add("rebuild", file, "", true)
+		}
+	} else {
+		add("rebuild", "pom.xml", "", true)
+	}
+
+	for _, watchPath := range opts.WatchPaths {
+		source := path.Clean(filepath.ToSlash(watchPath))
+		if sync {
+			add("sync", source, path.Join("/app", source), false)
+		} else {
+			add("rebuild", source, "", false)
+		}
+	}
+
+	return rules
+}
@@ -0,0 +1,63 @@
+package render
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultDebugPort is the first host port given to the JDWP agent of the services
+	DefaultDebugPort = 5005
+
+	// containerDebugPort is the port the JDWP agent listens on inside the containers
+	containerDebugPort = "5005"
+)
+
+// jdwpOptions returns the JVM options starting the JDWP agent without waiting for a debugger.
+// They are passed through JAVA_TOOL_OPTIONS, read by every JVM whatever the image.
+func jdwpOptions() string {
+	return "-agentlib:jdwp=transport=dt_socket,server=y,suspend=n,address=*:" + containerDebugPort
+}
+
+// debugPortOf returns the host debug port of a service, empty when debugging is disabled
+func debugPortOf(opts Options) string {
+	if !opts.Debug {
+		return ""
+	}
+	if opts.DebugPort != "" {
+		return opts.DebugPort
+	}
+	return strconv.Itoa(DefaultDebugPort)
+}
+
+// AssignDebugPorts gives a unique host debug port to every service with debugging enabled
+// and no port declared, from DefaultDebugPort on, skipping the ports already used.
+// The allocation only depends on the order of the services, so that it is the same
+// when generating the files and the IDE configurations.
+func AssignDebugPorts(services []Options) {
+	used := make(map[string]bool)
+	for _, opts := range services {
+		used[hostPort(opts.Port)] = true
+		if opts.Debug && opts.DebugPort != "" {
+			used[opts.DebugPort] = true
+		}
+	}
+
+	next := DefaultDebugPort
+	for i := range services {
+		if !services[i].Debug || services[i].DebugPort != "" {
+			continue
+		}
+		for used[strconv.Itoa(next)] {
+			next++
+		}
+		services[i].DebugPort = strconv.Itoa(next)
+		used[services[i].DebugPort] = true
+	}
+}
+
+// hostPort returns the host part of a port mapping (host:container)
+func hostPort(mapping string) string {
+	host, _, _ := strings.Cut(mapping, ":")
+	return host
+}
@@ -0,0 +1,99 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	if diff := UnifiedDiff("a", "b", []byte("same\n"), []byte("same\n")); diff != "" {
+		t.Errorf("No diff expected for identical contents, got:\n%s", diff)
+	}
+
+	oldContent := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	newContent := "1\ntwo\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n"
+	expected := `--- Tiltfile
++++ Tiltfile
+@@ -1,5 +1,5 @@
+ 1
+-2
++two
+ 3
+ 4
+ 5
+@@ -10,3 +10,4 @@
+ 10
+ 11
+ 12
++13
+`
+	if diff := UnifiedDiff("Tiltfile", "Tiltfile", []byte(oldContent), []byte(newContent)); diff != expected {
+		t.Errorf("Unexpected diff:\n%s\nexpected:\n%s", diff, expected)
+	}
+
+	expected = `--- /dev/null
++++ Tiltfile
+@@ -0,0 +1,2 @@
++a
++b
+`
+	if diff := UnifiedDiff("/dev/null", "Tiltfile", nil, []byte("a\nb\n")); diff != expected {
+		t.Errorf("Unexpected diff for a new file:\n%s\nexpected:\n%s", diff, expected)
+	}
+}
+
+func TestMemoryFileSystem(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Unable to get current working directory: %v", err)
+	}
+	defer os.Chdir(oldWd)
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Unable to change directory: %v", err)
+	}
+
+	// A hand-written file on disk is rendered in memory, and flagged as needing --force
+	if err := os.WriteFile("Tiltfile", []byte("# custom\n"), 0644); err != nil {
+		t.Fatalf("Unable to create Tiltfile: %v", err)
+	}
+
+	mem := NewMemoryFileSystem()
+	previous := SetFileSystem(mem)
+	defer SetFileSystem(previous)
+
+	opts := Options{Framework: "spring", AppName: "demo", Port: "8080", Path: ".", OutputDir: ".turbotilt"}
+	if err := GenerateCompose(opts); err != nil {
+		t.Fatalf("GenerateCompose returned an error: %v", err)
+	}
+	if err := writeGeneratedFile("Tiltfile", []byte("docker_compose('docker-compose.yml')\n"), false); err != nil {
+		t.Errorf("Previewing a hand-written file should not fail: %v", err)
+	}
+	if ownership, ok := mem.RequiresForce("Tiltfile"); !ok || ownership != FileForeign {
+		t.Errorf("The hand-written Tiltfile should require --force, got %v (%v)", ownership, ok)
+	}
+	if _, ok := mem.RequiresForce(filepath.Join(".turbotilt", "docker-compose.yml")); ok {
+		t.Error("A new file should not require --force")
+	}
+
+	if _, err := os.Stat(".turbotilt"); !os.IsNotExist(err) {
+		t.Error("Nothing should be written to disk")
+	}
+
+	files := mem.Files()
+	expected := []string{filepath.Join(".turbotilt", ".gitignore"), filepath.Join(".turbotilt", "docker-compose.yml"), "Tiltfile"}
+	if len(files) != len(expected) {
+		t.Fatalf("Unexpected files in memory: %v", files)
+	}
+	for i, file := range expected {
+		if files[i] != file {
+			t.Errorf("File %d should be %s, got %s", i, file, files[i])
+		}
+	}
+
+	content, ok := mem.Content(filepath.Join(".turbotilt", "docker-compose.yml"))
+	if !ok || ownershipOf(content) != FileOwned {
+		t.Errorf("The compose file should be stamped in memory:\n%s", content)
+	}
+}
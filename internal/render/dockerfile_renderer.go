@@ -2,7 +2,6 @@ package render
 
 import (
 	"io"
-	"text/template"
 )
 
 // DockerfileTemplates contains all Dockerfile templates
@@ -65,13 +64,18 @@ CMD ["sh", "start.sh"]
 // TemplateDockerfileRenderer is the implementation of DockerfileRenderer that uses templates
 type TemplateDockerfileRenderer struct{}
 
-// renderDockerfile executes a Dockerfile template with the specified options
+// renderDockerfile executes a Dockerfile template with the specified options.
+// A Dockerfile.<name>.tmpl project or user template replaces the built-in one.
 func (r *TemplateDockerfileRenderer) renderDockerfile(w io.Writer, tmplContent string, name string, opts Options) error {
-	tmpl, err := template.New(name).Parse(tmplContent)
+	ts := NewTemplateService()
+	ts.Embedded = nil
+	ts.Delimiters = [2]string{"{{", "}}"}
+
+	tmpl, err := ts.LoadTemplate(name, []string{"Dockerfile." + name + ".tmpl"}, tmplContent)
 	if err != nil {
 		return err
 	}
-	return tmpl.Execute(w, opts)
+	return tmpl.Execute(w, newServiceTemplateData(opts))
 }
 
 // RenderSpringDockerfile writes a Dockerfile for Spring Boot
@@ -0,0 +1,51 @@
+package render
+
+import "fmt"
+
+// Endpoint is an address of the generated environment, as listed by the status command
+type Endpoint struct {
+	Name string // Service or tool
+	Kind string // app, gateway, route, debug, observability, chaos, tool
+	URL  string
+}
+
+// Endpoints returns the addresses of the environment generated for the services:
+// ports of the application services, hostnames and routes of the gateway, debug ports and tools
+func Endpoints(serviceList ServiceList) []Endpoint {
+	var endpoints []Endpoint
+
+	// Without gateway, the applications serve HTTPS themselves
+	scheme := "http"
+	if serviceList.TLS.Enabled && !serviceList.Gateway.Enabled {
+		scheme = "https"
+	}
+	for _, opts := range serviceList.Services {
+		endpoints = append(endpoints, Endpoint{Name: imageName(opts), Kind: "app", URL: fmt.Sprintf("%s://localhost:%s", scheme, getOrDefault(opts.Port, DefaultPort))})
+	}
+
+	if serviceList.Gateway.Enabled {
+		for _, opts := range serviceList.Services {
+			endpoints = append(endpoints, Endpoint{Name: imageName(opts), Kind: "gateway", URL: GatewayURL(serviceList, imageName(opts))})
+		}
+		for _, route := range serviceList.Gateway.Routes {
+			endpoints = append(endpoints, Endpoint{Name: route.Service, Kind: "route", URL: GatewayURL(serviceList, route.Host) + route.Path})
+		}
+	}
+
+	for _, opts := range serviceList.Services {
+		if port := debugPortOf(opts); port != "" {
+			endpoints = append(endpoints, Endpoint{Name: imageName(opts), Kind: "debug", URL: "localhost:" + port})
+		}
+	}
+
+	if serviceList.Observability.Enabled {
+		endpoints = append(endpoints,
+			Endpoint{Name: "jaeger", Kind: "observability", URL: "http://localhost:16686"},
+			Endpoint{Name: "prometheus", Kind: "observability", URL: "http://localhost:9090"},
+			Endpoint{Name: "grafana", Kind: "observability", URL: "http://localhost:3000"},
+		)
+	}
+
+	endpoints = append(endpoints, chaosEndpoints(serviceList)...)
+	return append(endpoints, toolEndpoints(serviceList)...)
+}
@@ -0,0 +1,101 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// FileSystem is where the generated files are written
+type FileSystem interface {
+	ReadFile(name string) ([]byte, error)
+	WriteFile(name string, data []byte, perm os.FileMode) error
+	MkdirAll(path string, perm os.FileMode) error
+}
+
+// osFileSystem writes the generated files to disk
+type osFileSystem struct{}
+
+func (osFileSystem) ReadFile(name string) ([]byte, error) { return os.ReadFile(name) }
+
+func (osFileSystem) WriteFile(name string, data []byte, perm os.FileMode) error {
+	return os.WriteFile(name, data, perm)
+}
+
+func (osFileSystem) MkdirAll(path string, perm os.FileMode) error { return os.MkdirAll(path, perm) }
+
+// outputFS receives the generated files (variable to preview a generation without touching the disk)
+var outputFS FileSystem = osFileSystem{}
+
+// SetFileSystem replaces the filesystem receiving the generated files and returns the previous one
+func SetFileSystem(fs FileSystem) FileSystem {
+	previous := outputFS
+	outputFS = fs
+	return previous
+}
+
+// WriteFile writes a file that is not generated from a template (no header, no ownership check)
+// to the filesystem receiving the generated files
+func WriteFile(path string, data []byte) error {
+	return outputFS.WriteFile(path, data, 0644)
+}
+
+// previewFileSystem is a filesystem previewing a generation: the files Turbotilt would not overwrite
+// without --force are rendered anyway, and reported instead of failing the generation
+type previewFileSystem interface {
+	FileSystem
+	requireForce(name string, ownership FileOwnership)
+}
+
+// MemoryFileSystem keeps the generated files in memory.
+// Files it does not contain are read from disk, so ownership checks still apply.
+type MemoryFileSystem struct {
+	files  map[string][]byte
+	forced map[string]FileOwnership
+}
+
+// NewMemoryFileSystem creates an empty MemoryFileSystem
+func NewMemoryFileSystem() *MemoryFileSystem {
+	return &MemoryFileSystem{files: make(map[string][]byte), forced: make(map[string]FileOwnership)}
+}
+
+// ReadFile returns the content written in memory, or the content on disk
+func (m *MemoryFileSystem) ReadFile(name string) ([]byte, error) {
+	if data, ok := m.files[filepath.Clean(name)]; ok {
+		return append([]byte(nil), data...), nil
+	}
+	return os.ReadFile(name)
+}
+
+// WriteFile stores the content in memory
+func (m *MemoryFileSystem) WriteFile(name string, data []byte, perm os.FileMode) error {
+	m.files[filepath.Clean(name)] = append([]byte(nil), data...)
+	return nil
+}
+
+// MkdirAll does nothing, directories are implicit in memory
+func (m *MemoryFileSystem) MkdirAll(path string, perm os.FileMode) error {
+	return nil
+}
+
+// Files returns the paths of the files written, in alphabetical order
+func (m *MemoryFileSystem) Files() []string {
+	return sortedKeys(m.files)
+}
+
+// requireForce records that writing the file to disk needs --force
+func (m *MemoryFileSystem) requireForce(name string, ownership FileOwnership) {
+	m.forced[filepath.Clean(name)] = ownership
+}
+
+// RequiresForce returns the ownership of a file written in memory that Turbotilt would only
+// overwrite on disk with --force, and whether it is one
+func (m *MemoryFileSystem) RequiresForce(name string) (FileOwnership, bool) {
+	ownership, ok := m.forced[filepath.Clean(name)]
+	return ownership, ok
+}
+
+// Content returns the content written to a file and whether it was written
+func (m *MemoryFileSystem) Content(name string) ([]byte, bool) {
+	data, ok := m.files[filepath.Clean(name)]
+	return data, ok
+}
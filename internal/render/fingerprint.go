@@ -0,0 +1,75 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// FingerprintFile is the file of the output directory recording the inputs of the last generation
+const FingerprintFile = ".turbotilt-fingerprint.json"
+
+// Fingerprint records the content of the files a generation depends on,
+// so that generated files can be detected as stale when one of them changes
+type Fingerprint struct {
+	Inputs   map[string]string `json:"inputs"`             // SHA-256 of every input file, by path
+	InitArgs []string          `json:"initArgs,omitempty"` // init flags to regenerate the files with
+}
+
+// NewFingerprint hashes the given input files. Missing files are not recorded.
+func NewFingerprint(inputs []string, initArgs []string) Fingerprint {
+	fp := Fingerprint{Inputs: make(map[string]string), InitArgs: initArgs}
+	for _, input := range inputs {
+		content, err := os.ReadFile(input)
+		if err != nil {
+			continue
+		}
+		fp.Inputs[filepath.ToSlash(filepath.Clean(input))] = contentHash(content)
+	}
+	return fp
+}
+
+// Changes returns the inputs added, removed or modified in current, in alphabetical order
+func (f Fingerprint) Changes(current Fingerprint) []string {
+	changed := make(map[string]bool)
+	for path, hash := range current.Inputs {
+		if f.Inputs[path] != hash {
+			changed[path] = true
+		}
+	}
+	for path := range f.Inputs {
+		if _, ok := current.Inputs[path]; !ok {
+			changed[path] = true
+		}
+	}
+	return sortedKeys(changed)
+}
+
+// WriteFingerprint records the fingerprint in the output directory
+func WriteFingerprint(outputDir string, fp Fingerprint) error {
+	data, err := json.MarshalIndent(fp, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := ensureOutputDir(outputDir); err != nil {
+		return fmt.Errorf("error creating output directory: %w", err)
+	}
+	return outputFS.WriteFile(outputPath(outputDir, FingerprintFile), append(data, '\n'), 0644)
+}
+
+// ReadFingerprint reads the fingerprint recorded in the output directory
+func ReadFingerprint(outputDir string) (Fingerprint, error) {
+	var fp Fingerprint
+
+	data, err := outputFS.ReadFile(outputPath(outputDir, FingerprintFile))
+	if err != nil {
+		return fp, err
+	}
+
+	if err := json.Unmarshal(data, &fp); err != nil {
+		return fp, fmt.Errorf("invalid fingerprint %s: %w", FingerprintFile, err)
+	}
+	return fp, nil
+}
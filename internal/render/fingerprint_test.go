@@ -0,0 +1,64 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFingerprintChanges(t *testing.T) {
+	tempDir := t.TempDir()
+	manifest := filepath.Join(tempDir, "turbotilt.yaml")
+	pom := filepath.Join(tempDir, "pom.xml")
+	gradle := filepath.Join(tempDir, "build.gradle")
+
+	if err := os.WriteFile(manifest, []byte("services: []\n"), 0644); err != nil {
+		t.Fatalf("Unable to create manifest: %v", err)
+	}
+	if err := os.WriteFile(pom, []byte("<project/>\n"), 0644); err != nil {
+		t.Fatalf("Unable to create pom.xml: %v", err)
+	}
+
+	inputs := []string{manifest, pom, gradle}
+	recorded := NewFingerprint(inputs, []string{"--port=9090"})
+	if len(recorded.Inputs) != 2 {
+		t.Fatalf("Missing files should not be recorded: %v", recorded.Inputs)
+	}
+
+	if err := WriteFingerprint(tempDir, recorded); err != nil {
+		t.Fatalf("WriteFingerprint returned an error: %v", err)
+	}
+	read, err := ReadFingerprint(tempDir)
+	if err != nil {
+		t.Fatalf("ReadFingerprint returned an error: %v", err)
+	}
+	if len(read.InitArgs) != 1 || read.InitArgs[0] != "--port=9090" {
+		t.Errorf("The init flags were not recorded: %v", read.InitArgs)
+	}
+
+	if changes := read.Changes(NewFingerprint(inputs, nil)); len(changes) != 0 {
+		t.Errorf("No change expected, got %v", changes)
+	}
+
+	// Modified, added and removed inputs are all reported
+	if err := os.WriteFile(manifest, []byte("services:\n  - name: api\n"), 0644); err != nil {
+		t.Fatalf("Unable to update manifest: %v", err)
+	}
+	if err := os.WriteFile(gradle, []byte("plugins {}\n"), 0644); err != nil {
+		t.Fatalf("Unable to create build.gradle: %v", err)
+	}
+	if err := os.Remove(pom); err != nil {
+		t.Fatalf("Unable to remove pom.xml: %v", err)
+	}
+
+	changes := read.Changes(NewFingerprint(inputs, nil))
+	expected := []string{filepath.ToSlash(gradle), filepath.ToSlash(pom), filepath.ToSlash(manifest)}
+	if len(changes) != len(expected) {
+		t.Fatalf("Expected changes %v, got %v", expected, changes)
+	}
+	for i, change := range expected {
+		if changes[i] != change {
+			t.Errorf("Change %d should be %s, got %s", i, change, changes[i])
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// DefaultGatewayPort is the host port of the gateway when none is set
+	DefaultGatewayPort = "80"
+
+	// DefaultGatewayTLSPort is the HTTPS host port of the gateway when none is set
+	DefaultGatewayTLSPort = "443"
+
+	// gatewayDomain is the domain of the service hostnames, resolved to the loopback address
+	gatewayDomain = "localhost"
+)
+
+// GatewayOptions configures the Traefik gateway routing <service>.localhost to every application service
+type GatewayOptions struct {
+	Enabled bool
+	Port    string         // Host port of the gateway (DefaultGatewayPort when empty)
+	TLSPort string         // HTTPS host port of the gateway when TLS is enabled (DefaultGatewayTLSPort when empty)
+	Routes  []GatewayRoute // Path routes, e.g. /api of the frontend hostname to a backend
+}
+
+// GatewayRoute sends the requests for a path of a service hostname to another service
+type GatewayRoute struct {
+	Host        string // Service whose hostname receives the route (the frontend)
+	Path        string // Path prefix, e.g. /api
+	Service     string // Service receiving the requests (the backend)
+	StripPrefix bool   // Remove the path prefix before forwarding
+}
+
+// GatewayHost returns the hostname of a service behind the gateway
+func GatewayHost(service string) string {
+	return service + "." + gatewayDomain
+}
+
+// GatewayURL returns the URL of a service hostname behind the gateway, in HTTPS when TLS is enabled
+func GatewayURL(serviceList ServiceList, service string) string {
+	scheme, port, defaultPort := "http", getOrDefault(serviceList.Gateway.Port, DefaultGatewayPort), "80"
+	if serviceList.TLS.Enabled {
+		scheme, port, defaultPort = "https", getOrDefault(serviceList.Gateway.TLSPort, DefaultGatewayTLSPort), "443"
+	}
+
+	if port == defaultPort {
+		return fmt.Sprintf("%s://%s", scheme, GatewayHost(service))
+	}
+	return fmt.Sprintf("%s://%s:%s", scheme, GatewayHost(service), port)
+}
+
+// gatewayServiceDefinition returns the compose definition of the Traefik gateway, reading the
+// routes from the labels of the application services
+func gatewayServiceDefinition(options GatewayOptions) ComposeServiceDefinition {
+	return ComposeServiceDefinition{
+		Name:    "gateway",
+		Image:   "traefik:v3.1",
+		Command: "--providers.docker=true --providers.docker.exposedbydefault=false --entrypoints.http.address=:80",
+		Port:    fmt.Sprintf("%s:80", getOrDefault(options.Port, DefaultGatewayPort)),
+		Volumes: []string{"/var/run/docker.sock:/var/run/docker.sock:ro"},
+	}
+}
+
+// routeService adds the Traefik labels routing the hostname of an application service to it,
+// and the path routes sending requests of other hostnames to it
+func routeService(service *ComposeServiceDefinition, opts Options, serviceList ServiceList) {
+	name := service.Name
+	options := serviceList.Gateway
+	if service.Labels == nil {
+		service.Labels = make(map[string]string)
+	}
+
+	// With TLS, the gateway redirects HTTP to HTTPS
+	entrypoints := "http"
+	if serviceList.TLS.Enabled {
+		entrypoints = "https"
+	}
+
+	service.Labels["traefik.enable"] = "true"
+	service.Labels[fmt.Sprintf("traefik.http.services.%s.loadbalancer.server.port", name)] = getOrDefault(opts.Port, DefaultPort)
+	service.Labels[fmt.Sprintf("traefik.http.routers.%s.rule", name)] = fmt.Sprintf("Host(`%s`)", GatewayHost(name))
+	service.Labels[fmt.Sprintf("traefik.http.routers.%s.entrypoints", name)] = entrypoints
+	service.Labels[fmt.Sprintf("traefik.http.routers.%s.service", name)] = name
+	if serviceList.TLS.Enabled {
+		service.Labels[fmt.Sprintf("traefik.http.routers.%s.tls", name)] = "true"
+	}
+
+	for _, route := range options.Routes {
+		if route.Service != name {
+			continue
+		}
+
+		// The longer rule has the priority over the hostname router of the frontend
+		router := fmt.Sprintf("%s-%s-%s", route.Host, name, routeSlug(route.Path))
+		service.Labels[fmt.Sprintf("traefik.http.routers.%s.rule", router)] = fmt.Sprintf("Host(`%s`) && PathPrefix(`%s`)", GatewayHost(route.Host), route.Path)
+		service.Labels[fmt.Sprintf("traefik.http.routers.%s.entrypoints", router)] = entrypoints
+		service.Labels[fmt.Sprintf("traefik.http.routers.%s.service", router)] = name
+		if serviceList.TLS.Enabled {
+			service.Labels[fmt.Sprintf("traefik.http.routers.%s.tls", router)] = "true"
+		}
+		if route.StripPrefix {
+			service.Labels[fmt.Sprintf("traefik.http.middlewares.%s-strip.stripprefix.prefixes", router)] = route.Path
+			service.Labels[fmt.Sprintf("traefik.http.routers.%s.middlewares", router)] = router + "-strip"
+		}
+	}
+}
+
+// routeSlug turns a path into a name usable in Traefik router names
+func routeSlug(path string) string {
+	slug := strings.Trim(strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '-'
+	}, path), "-")
+	if slug == "" {
+		return "root"
+	}
+	return strings.ToLower(slug)
+}
@@ -0,0 +1,63 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGateway(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Unable to get current working directory: %v", err)
+	}
+	defer os.Chdir(oldWd)
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Unable to change directory: %v", err)
+	}
+
+	serviceList := ServiceList{
+		OutputDir: ".turbotilt",
+		Gateway: GatewayOptions{
+			Enabled: true,
+			Port:    "8000",
+			Routes:  []GatewayRoute{{Host: "web", Path: "/api", Service: "users", StripPrefix: true}},
+		},
+		Services: []Options{
+			{ServiceName: "web", Framework: "spring", Port: "8081", Path: "web", OutputDir: ".turbotilt"},
+			{ServiceName: "users", Framework: "spring", Port: "8082", Path: "users", OutputDir: ".turbotilt"},
+		},
+	}
+	if err := GenerateMultiServiceCompose(serviceList); err != nil {
+		t.Fatalf("GenerateMultiServiceCompose returned an error: %v", err)
+	}
+
+	compose, err := os.ReadFile(filepath.Join(".turbotilt", "docker-compose.yml"))
+	if err != nil {
+		t.Fatalf("Unable to read docker-compose.yml: %v", err)
+	}
+	for _, expected := range []string{
+		"  gateway:\n    image: traefik:v3.1\n",
+		"- '8000:80'",
+		"- 'traefik.http.routers.users.rule=Host(`users.localhost`)'",
+		"- 'traefik.http.services.users.loadbalancer.server.port=8082'",
+		"- 'traefik.http.routers.web-users-api.rule=Host(`web.localhost`) && PathPrefix(`/api`)'",
+		"- 'traefik.http.routers.web-users-api.middlewares=web-users-api-strip'",
+	} {
+		if !strings.Contains(string(compose), expected) {
+			t.Errorf("docker-compose.yml should contain %q:\n%s", expected, compose)
+		}
+	}
+
+	urls := make(map[string]bool)
+	for _, endpoint := range Endpoints(serviceList) {
+		urls[endpoint.Kind+" "+endpoint.URL] = true
+	}
+	for _, expected := range []string{"app http://localhost:8081", "gateway http://users.localhost:8000", "route http://web.localhost:8000/api"} {
+		if !urls[expected] {
+			t.Errorf("Endpoints should contain %q, got %v", expected, urls)
+		}
+	}
+}
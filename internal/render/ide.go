@@ -0,0 +1,144 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	// VSCodeLaunchFile is the VS Code file receiving the remote-attach configurations
+	VSCodeLaunchFile = ".vscode/launch.json"
+
+	// IntelliJRunDir is the IntelliJ IDEA folder of the shared run configurations
+	IntelliJRunDir = ".run"
+
+	// ideConfigSuffix marks the IDE configurations managed by Turbotilt
+	ideConfigSuffix = " (turbotilt)"
+
+	// intelliJGeneratedMarker is the first line of the run configurations generated by Turbotilt.
+	// IntelliJ IDEA drops it when the configuration is edited, which then protects the edits.
+	intelliJGeneratedMarker = "<!-- Generated by turbotilt - do not edit, run 'turbotilt ide' instead -->"
+)
+
+// debugServices returns the services with remote debugging enabled
+func debugServices(services []Options) []Options {
+	var debug []Options
+	for _, opts := range services {
+		if opts.Debug {
+			debug = append(debug, opts)
+		}
+	}
+	return debug
+}
+
+// ideConfigName returns the name of the remote-attach configuration of a service
+func ideConfigName(opts Options) string {
+	return "Debug " + imageName(opts) + ideConfigSuffix
+}
+
+// GenerateVSCodeLaunch adds a remote-attach configuration per debugged service to .vscode/launch.json.
+// Configurations not managed by Turbotilt are kept; a launch.json that cannot be read as JSON
+// (e.g. with comments) is only replaced with force.
+func GenerateVSCodeLaunch(services []Options, force bool) (string, error) {
+	launch := map[string]interface{}{"version": "0.2.0"}
+	var configurations []interface{}
+
+	content, err := outputFS.ReadFile(VSCodeLaunchFile)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("error reading %s: %w", VSCodeLaunchFile, err)
+	}
+	if err == nil {
+		if jsonErr := json.Unmarshal(content, &launch); jsonErr != nil {
+			if !force {
+				return "", fmt.Errorf("%s cannot be updated, it is not plain JSON (use --force to overwrite it)", VSCodeLaunchFile)
+			}
+			launch = map[string]interface{}{"version": "0.2.0"}
+		}
+		existing, _ := launch["configurations"].([]interface{})
+		for _, configuration := range existing {
+			if entry, ok := configuration.(map[string]interface{}); ok {
+				if name, _ := entry["name"].(string); strings.HasSuffix(name, ideConfigSuffix) {
+					continue
+				}
+			}
+			configurations = append(configurations, configuration)
+		}
+	}
+
+	for _, opts := range debugServices(services) {
+		port, _ := strconv.Atoi(debugPortOf(opts))
+		configurations = append(configurations, map[string]interface{}{
+			"type":        "java",
+			"name":        ideConfigName(opts),
+			"request":     "attach",
+			"hostName":    "localhost",
+			"port":        port,
+			"projectName": imageName(opts),
+		})
+	}
+	launch["configurations"] = configurations
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(launch); err != nil {
+		return "", fmt.Errorf("error encoding %s: %w", VSCodeLaunchFile, err)
+	}
+
+	if err := outputFS.MkdirAll(filepath.Dir(VSCodeLaunchFile), 0755); err != nil {
+		return "", fmt.Errorf("error creating directory %s: %w", filepath.Dir(VSCodeLaunchFile), err)
+	}
+	if err := outputFS.WriteFile(VSCodeLaunchFile, buf.Bytes(), 0644); err != nil {
+		return "", fmt.Errorf("error writing %s: %w", VSCodeLaunchFile, err)
+	}
+	return VSCodeLaunchFile, nil
+}
+
+// GenerateIntelliJRunConfigs writes a remote JVM debug run configuration per debugged service
+// to .run/debug-<service>.run.xml, shared by IntelliJ IDEA with the project
+func GenerateIntelliJRunConfigs(services []Options, force bool) ([]string, error) {
+	var paths []string
+
+	for _, opts := range debugServices(services) {
+		path := filepath.Join(IntelliJRunDir, fmt.Sprintf("debug-%s.run.xml", imageName(opts)))
+
+		content, err := outputFS.ReadFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return paths, fmt.Errorf("error reading %s: %w", path, err)
+		}
+		if err == nil && !force && !strings.HasPrefix(string(content), intelliJGeneratedMarker) {
+			return paths, &OverwriteError{Path: path, Ownership: FileForeign}
+		}
+
+		var sb strings.Builder
+		sb.WriteString(intelliJGeneratedMarker + "\n")
+		sb.WriteString("<component name=\"ProjectRunConfigurationManager\">\n")
+		sb.WriteString(fmt.Sprintf("  <configuration default=\"false\" name=\"%s\" type=\"Remote\">\n", ideConfigName(opts)))
+		sb.WriteString("    <option name=\"USE_SOCKET_TRANSPORT\" value=\"true\" />\n")
+		sb.WriteString("    <option name=\"SERVER_MODE\" value=\"false\" />\n")
+		sb.WriteString("    <option name=\"SHMEM_ADDRESS\" />\n")
+		sb.WriteString("    <option name=\"HOST\" value=\"localhost\" />\n")
+		sb.WriteString(fmt.Sprintf("    <option name=\"PORT\" value=\"%s\" />\n", debugPortOf(opts)))
+		sb.WriteString("    <option name=\"AUTO_RESTART\" value=\"false\" />\n")
+		sb.WriteString("    <method v=\"2\" />\n")
+		sb.WriteString("  </configuration>\n")
+		sb.WriteString("</component>\n")
+
+		if err := outputFS.MkdirAll(IntelliJRunDir, 0755); err != nil {
+			return paths, fmt.Errorf("error creating directory %s: %w", IntelliJRunDir, err)
+		}
+		if err := outputFS.WriteFile(path, []byte(sb.String()), 0644); err != nil {
+			return paths, fmt.Errorf("error writing %s: %w", path, err)
+		}
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
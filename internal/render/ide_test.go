@@ -0,0 +1,101 @@
+package render
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAssignDebugPorts(t *testing.T) {
+	services := []Options{
+		{ServiceName: "api", Port: "5005", Debug: true},
+		{ServiceName: "web", Port: "8081"},
+		{ServiceName: "billing", Port: "8082", Debug: true, DebugPort: "5006"},
+		{ServiceName: "orders", Port: "8083", Debug: true},
+	}
+
+	AssignDebugPorts(services)
+
+	expected := map[string]string{"api": "5007", "web": "", "billing": "5006", "orders": "5008"}
+	for _, opts := range services {
+		if opts.DebugPort != expected[opts.ServiceName] {
+			t.Errorf("%s: expected debug port %q, got %q", opts.ServiceName, expected[opts.ServiceName], opts.DebugPort)
+		}
+	}
+}
+
+func TestGenerateIDEConfigs(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Unable to get current working directory: %v", err)
+	}
+	defer os.Chdir(oldWd)
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Unable to change directory: %v", err)
+	}
+
+	// Configurations of the user are kept
+	existing := `{"version": "0.2.0", "configurations": [{"type": "java", "name": "Launch Main", "request": "launch"}]}`
+	if err := os.MkdirAll(".vscode", 0755); err != nil {
+		t.Fatalf("Unable to create .vscode: %v", err)
+	}
+	if err := os.WriteFile(VSCodeLaunchFile, []byte(existing), 0644); err != nil {
+		t.Fatalf("Unable to write launch.json: %v", err)
+	}
+
+	services := []Options{
+		{ServiceName: "api", Port: "8080", Debug: true, DebugPort: "5005"},
+		{ServiceName: "web", Port: "8081"},
+	}
+
+	// Generating twice replaces the configurations of Turbotilt
+	for i := 0; i < 2; i++ {
+		if _, err := GenerateVSCodeLaunch(services, false); err != nil {
+			t.Fatalf("GenerateVSCodeLaunch returned an error: %v", err)
+		}
+	}
+
+	content, err := os.ReadFile(VSCodeLaunchFile)
+	if err != nil {
+		t.Fatalf("Unable to read launch.json: %v", err)
+	}
+	var launch struct {
+		Configurations []map[string]interface{} `json:"configurations"`
+	}
+	if err := json.Unmarshal(content, &launch); err != nil {
+		t.Fatalf("launch.json is not valid JSON: %v", err)
+	}
+	if len(launch.Configurations) != 2 {
+		t.Fatalf("Expected the user configuration and one attach configuration, got:\n%s", content)
+	}
+	if launch.Configurations[1]["name"] != "Debug api (turbotilt)" || launch.Configurations[1]["port"] != float64(5005) {
+		t.Errorf("Unexpected attach configuration: %v", launch.Configurations[1])
+	}
+
+	// IntelliJ IDEA run configurations are only written for debugged services
+	paths, err := GenerateIntelliJRunConfigs(services, false)
+	if err != nil {
+		t.Fatalf("GenerateIntelliJRunConfigs returned an error: %v", err)
+	}
+	if len(paths) != 1 || paths[0] != filepath.Join(IntelliJRunDir, "debug-api.run.xml") {
+		t.Fatalf("Unexpected run configurations: %v", paths)
+	}
+	runConfig, err := os.ReadFile(paths[0])
+	if err != nil {
+		t.Fatalf("Unable to read the run configuration: %v", err)
+	}
+	if !strings.Contains(string(runConfig), `<option name="PORT" value="5005" />`) {
+		t.Errorf("The run configuration should attach to port 5005:\n%s", runConfig)
+	}
+
+	// A run configuration edited in the IDE is not overwritten without force
+	if err := os.WriteFile(paths[0], []byte("<component/>"), 0644); err != nil {
+		t.Fatalf("Unable to write the run configuration: %v", err)
+	}
+	if _, err := GenerateIntelliJRunConfigs(services, false); err == nil {
+		t.Error("An edited run configuration should not be overwritten without force")
+	}
+}
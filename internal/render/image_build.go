@@ -0,0 +1,141 @@
+package render
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"turbotilt/internal/scan"
+)
+
+// ResolveBuildStrategy returns the image build strategy of a service, detecting it when not set
+func ResolveBuildStrategy(opts Options) scan.ImageBuildStrategy {
+	if opts.BuildStrategy != "" {
+		return opts.BuildStrategy
+	}
+
+	// A Dockerfile generated by a previous run is not a project Dockerfile
+	info := scan.DetectImageBuild(servicePathOf(opts))
+	if info.Strategy == scan.BuildExistingDockerfile {
+		if ownership, err := CheckOwnership(info.Dockerfile); err == nil && ownership != FileForeign {
+			return scan.DetectPluginImageBuild(servicePathOf(opts)).Strategy
+		}
+	}
+	return info.Strategy
+}
+
+// resolveBuildSystem returns the build system of a service, detecting it when not set
+func resolveBuildSystem(opts Options) string {
+	if opts.BuildSystem != "" {
+		return opts.BuildSystem
+	}
+	if buildSystem := scan.DetectBuildSystem(servicePathOf(opts)); buildSystem != "" {
+		return buildSystem
+	}
+	return "maven"
+}
+
+// UsesDockerfile indicates if the image of the service is built from a Dockerfile (generated or existing)
+func UsesDockerfile(opts Options) bool {
+	strategy := ResolveBuildStrategy(opts)
+	return strategy == scan.BuildGeneratedDockerfile || strategy == scan.BuildExistingDockerfile
+}
+
+// UsesGeneratedDockerfile indicates if Turbotilt has to generate a Dockerfile for the service
+func UsesGeneratedDockerfile(opts Options) bool {
+	return ResolveBuildStrategy(opts) == scan.BuildGeneratedDockerfile
+}
+
+// ImageBuildCommand returns the command building the service image for Tilt's custom_build.
+// It returns an empty string when the image is built from a Dockerfile.
+func ImageBuildCommand(opts Options) string {
+	strategy := ResolveBuildStrategy(opts)
+	buildSystem := resolveBuildSystem(opts)
+	servicePath := servicePathOf(opts)
+
+	var command string
+	switch strategy {
+	case scan.BuildJib:
+		if buildSystem == "gradle" {
+			command = gradleCommand(servicePath) + " jibDockerBuild --image=$EXPECTED_REF"
+		} else {
+			command = mavenCommand(servicePath) + " compile jib:dockerBuild -Dimage=$EXPECTED_REF"
+		}
+	case scan.BuildBuildpacks:
+		if buildSystem == "gradle" {
+			command = gradleCommand(servicePath) + " bootBuildImage --imageName=$EXPECTED_REF"
+		} else {
+			command = mavenCommand(servicePath) + " spring-boot:build-image -Dspring-boot.build-image.imageName=$EXPECTED_REF"
+		}
+	default:
+		return ""
+	}
+
+	// The command runs from the directory of the Tiltfile
+	if serviceDir := serviceDirFromOutput(opts); serviceDir != "." {
+		command = fmt.Sprintf("cd %s && %s", serviceDir, command)
+	}
+	return command
+}
+
+// gradleBuildFiles are the files of a Gradle project whose changes rebuild its image
+var gradleBuildFiles = []string{"build.gradle", "build.gradle.kts", "settings.gradle", "settings.gradle.kts"}
+
+// ImageBuildDeps returns the paths that trigger a rebuild of the service image in Tilt,
+// relative to the directory of the Tiltfile
+func ImageBuildDeps(opts Options) []string {
+	servicePath := serviceDirFromOutput(opts)
+	deps := []string{servicePath + "/src"}
+
+	if resolveBuildSystem(opts) == "gradle" {
+		// Groovy or Kotlin DSL build and settings files, whichever the project has
+		found := false
+		for _, file := range gradleBuildFiles {
+			if _, err := os.Stat(filepath.Join(servicePathOf(opts), file)); err == nil {
+				deps = append(deps, servicePath+"/"+file)
+				found = true
+			}
+		}
+		if !found {
+			deps = append(deps, servicePath+"/build.gradle")
+		}
+	} else {
+		deps = append(deps, servicePath+"/pom.xml")
+	}
+	return deps
+}
+
+// imageName returns the image reference of a service built outside of Docker Compose: the compose
+// service references it, and Tilt's custom_build builds it under the same name
+func imageName(opts Options) string {
+	if opts.ServiceName != "" {
+		return opts.ServiceName
+	}
+	if opts.AppName != "" {
+		return opts.AppName
+	}
+	return "app"
+}
+
+// servicePathOf returns the path of the service, defaulting to the current directory
+func servicePathOf(opts Options) string {
+	if opts.Path == "" {
+		return "."
+	}
+	return opts.Path
+}
+
+// mavenCommand returns the Maven executable to use, preferring the project wrapper
+func mavenCommand(servicePath string) string {
+	if _, err := os.Stat(filepath.Join(servicePath, "mvnw")); err == nil {
+		return "./mvnw"
+	}
+	return "mvn"
+}
+
+// gradleCommand returns the Gradle executable to use, preferring the project wrapper
+func gradleCommand(servicePath string) string {
+	if _, err := os.Stat(filepath.Join(servicePath, "gradlew")); err == nil {
+		return "./gradlew"
+	}
+	return "gradle"
+}
@@ -0,0 +1,109 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"turbotilt/internal/scan"
+)
+
+func TestGenerateDockerfileKeepsExistingDockerfile(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Unable to get current working directory: %v", err)
+	}
+	defer os.Chdir(oldWd)
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Unable to change directory: %v", err)
+	}
+
+	original := "FROM custom/image:1.0\n"
+	if err := os.WriteFile("Dockerfile", []byte(original), 0644); err != nil {
+		t.Fatalf("Unable to create Dockerfile: %v", err)
+	}
+
+	opts := Options{Framework: "spring", Port: "8080", JDKVersion: "17", Path: "."}
+	if err := GenerateDockerfile(opts); err != nil {
+		t.Fatalf("GenerateDockerfile returned an error: %v", err)
+	}
+
+	content, err := os.ReadFile("Dockerfile")
+	if err != nil {
+		t.Fatalf("Unable to read Dockerfile: %v", err)
+	}
+	if string(content) != original {
+		t.Errorf("The existing Dockerfile was overwritten:\n%s", content)
+	}
+}
+
+func TestJibBuildStrategy(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Unable to get current working directory: %v", err)
+	}
+	defer os.Chdir(oldWd)
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Unable to change directory: %v", err)
+	}
+
+	opts := Options{
+		ServiceName:   "orders",
+		AppName:       "orders-app",
+		Framework:     "spring",
+		Port:          "8080",
+		Path:          "./orders",
+		BuildStrategy: scan.BuildJib,
+		BuildSystem:   "gradle",
+	}
+
+	if command := ImageBuildCommand(opts); command != "cd ./orders && gradle jibDockerBuild --image=$EXPECTED_REF" {
+		t.Errorf("Unexpected build command: %s", command)
+	}
+
+	if err := GenerateComposeWithServices(opts); err != nil {
+		t.Fatalf("GenerateComposeWithServices returned an error: %v", err)
+	}
+	compose, _ := os.ReadFile("docker-compose.yml")
+	if !strings.Contains(string(compose), "image: orders") || strings.Contains(string(compose), "build:") {
+		t.Errorf("The compose service should reference the Jib image instead of building it:\n%s", compose)
+	}
+
+	if err := GenerateTiltfile(opts); err != nil {
+		t.Fatalf("GenerateTiltfile returned an error: %v", err)
+	}
+	tiltfile, _ := os.ReadFile("Tiltfile")
+	if !strings.Contains(string(tiltfile), "custom_build(") || !strings.Contains(string(tiltfile), "'./orders/build.gradle'") {
+		t.Errorf("The Tiltfile should use custom_build for Jib:\n%s", tiltfile)
+	}
+	// Tilt only replaces the image of the compose service when it builds the same reference
+	if !strings.Contains(string(tiltfile), "custom_build(\n  'orders',") {
+		t.Errorf("custom_build should build the image of the compose service:\n%s", tiltfile)
+	}
+
+	if _, err := os.Stat("orders/Dockerfile"); err == nil {
+		t.Error("No Dockerfile should be generated for a Jib project")
+	}
+
+	// The Kotlin DSL build and settings files rebuild the image as well
+	if err := os.MkdirAll("orders", 0755); err != nil {
+		t.Fatalf("Unable to create orders: %v", err)
+	}
+	for _, file := range []string{"build.gradle.kts", "settings.gradle.kts"} {
+		if err := os.WriteFile(filepath.Join("orders", file), []byte("plugins {}\n"), 0644); err != nil {
+			t.Fatalf("Unable to write %s: %v", file, err)
+		}
+	}
+	if deps := ImageBuildDeps(opts); strings.Join(deps, ",") != "./orders/src,./orders/build.gradle.kts,./orders/settings.gradle.kts" {
+		t.Errorf("Unexpected build dependencies: %v", deps)
+	}
+	if err := GenerateDockerfile(opts); err != nil {
+		t.Fatalf("GenerateDockerfile returned an error: %v", err)
+	}
+	if _, err := os.Stat("orders/Dockerfile"); err == nil {
+		t.Error("No Dockerfile should be generated for a Jib project")
+	}
+}
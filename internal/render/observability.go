@@ -0,0 +1,191 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"turbotilt/internal/scan"
+)
+
+const (
+	// DefaultOtelAgentVersion is the version of the OpenTelemetry Java agent used when none is set
+	DefaultOtelAgentVersion = "2.10.0"
+
+	// observabilityDir is the folder of the output directory receiving the configuration of the stack
+	observabilityDir = "observability"
+
+	// otelAgentPath is where the OpenTelemetry Java agent is mounted in the application containers
+	otelAgentPath = "/otel/opentelemetry-javaagent.jar"
+)
+
+// ObservabilityOptions configures the observability stack added to the generated environment:
+// OpenTelemetry collector, Jaeger, Prometheus and Grafana
+type ObservabilityOptions struct {
+	Enabled      bool   // Add the stack and instrument the application services
+	AgentVersion string // OpenTelemetry Java agent version (DefaultOtelAgentVersion when empty)
+}
+
+// instrumentService attaches the OpenTelemetry Java agent to an application service and
+// sends its telemetry to the collector
+func instrumentService(service *ComposeServiceDefinition, opts Options) {
+	service.Volumes = append(service.Volumes, "otel_agent:/otel:ro")
+	service.Completed = append(service.Completed, "otel-agent")
+	service.DependsOn = append(service.DependsOn, "otel-collector")
+
+	javaToolOptions := "-javaagent:" + otelAgentPath
+	if existing := service.Environment["JAVA_TOOL_OPTIONS"]; existing != "" {
+		javaToolOptions += " " + existing
+	}
+	service.Environment["JAVA_TOOL_OPTIONS"] = javaToolOptions
+	service.Environment["OTEL_SERVICE_NAME"] = service.Name
+	service.Environment["OTEL_EXPORTER_OTLP_ENDPOINT"] = "http://otel-collector:4318"
+	service.Environment["OTEL_EXPORTER_OTLP_PROTOCOL"] = "http/protobuf"
+	service.Environment["OTEL_LOGS_EXPORTER"] = "none"
+
+	// Actuator only exposes the Prometheus endpoint when asked to
+	if opts.Framework == "spring" && scan.DetectMetricsEndpoint(servicePathOf(opts), opts.Framework) != "" {
+		service.Environment["MANAGEMENT_ENDPOINTS_WEB_EXPOSURE_INCLUDE"] = "health,info,prometheus"
+	}
+}
+
+// observabilityServiceDefinitions returns the compose definitions and named volumes of the stack.
+// Configuration files are mounted from the observability folder next to docker-compose.yml.
+func observabilityServiceDefinitions(options ObservabilityOptions) ([]ComposeServiceDefinition, []string) {
+	return []ComposeServiceDefinition{
+		{
+			// One-shot service copying the agent into a volume shared with the applications
+			Name:    "otel-agent",
+			Image:   fmt.Sprintf("ghcr.io/open-telemetry/opentelemetry-operator/autoinstrumentation-java:%s", getOrDefault(options.AgentVersion, DefaultOtelAgentVersion)),
+			Command: fmt.Sprintf("cp /javaagent.jar %s", otelAgentPath),
+			Volumes: []string{"otel_agent:/otel"},
+		},
+		{
+			Name:      "otel-collector",
+			Image:     "otel/opentelemetry-collector-contrib:0.115.1",
+			Port:      "4318:4318",
+			Volumes:   []string{"./" + observabilityDir + "/otel-collector.yaml:/etc/otelcol-contrib/config.yaml:ro"},
+			DependsOn: []string{"jaeger"},
+		},
+		{
+			Name:  "jaeger",
+			Image: "jaegertracing/all-in-one:1.64.0",
+			Port:  "16686:16686",
+			Environment: map[string]string{
+				"COLLECTOR_OTLP_ENABLED": "true",
+			},
+		},
+		{
+			Name:      "prometheus",
+			Image:     "prom/prometheus:v3.0.1",
+			Port:      "9090:9090",
+			Volumes:   []string{"./" + observabilityDir + "/prometheus.yml:/etc/prometheus/prometheus.yml:ro"},
+			DependsOn: []string{"otel-collector"},
+		},
+		{
+			Name:  "grafana",
+			Image: "grafana/grafana:11.4.0",
+			Port:  "3000:3000",
+			Environment: map[string]string{
+				"GF_AUTH_ANONYMOUS_ENABLED":  "true",
+				"GF_AUTH_ANONYMOUS_ORG_ROLE": "Admin",
+			},
+			Volumes:   []string{"./" + observabilityDir + "/grafana-datasources.yaml:/etc/grafana/provisioning/datasources/datasources.yaml:ro"},
+			DependsOn: []string{"prometheus", "jaeger"},
+		},
+	}, []string{"otel_agent"}
+}
+
+// generateObservabilityConfig writes the configuration files of the stack in the output directory
+func generateObservabilityConfig(serviceList ServiceList) error {
+	files := map[string]string{
+		"otel-collector.yaml":      otelCollectorConfig,
+		"prometheus.yml":           prometheusConfig(serviceList.Services),
+		"grafana-datasources.yaml": grafanaDatasources,
+	}
+
+	for _, name := range sortedKeys(files) {
+		path := observabilityDir + "/" + name
+		if err := writeOutputFile(serviceList.OutputDir, path, []byte(files[name]), serviceList.Force); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ObservabilityFiles returns the configuration files of the stack, relative to the output directory
+func ObservabilityFiles() []string {
+	return []string{
+		observabilityDir + "/otel-collector.yaml",
+		observabilityDir + "/prometheus.yml",
+		observabilityDir + "/grafana-datasources.yaml",
+	}
+}
+
+// prometheusConfig returns the Prometheus configuration scraping the collector and the
+// Micrometer endpoints detected in the application services
+func prometheusConfig(services []Options) string {
+	var sb strings.Builder
+	sb.WriteString("global:\n")
+	sb.WriteString("  scrape_interval: 15s\n\n")
+	sb.WriteString("scrape_configs:\n")
+	sb.WriteString("  - job_name: otel-collector\n")
+	sb.WriteString("    static_configs:\n")
+	sb.WriteString("      - targets: ['otel-collector:8889']\n")
+
+	for _, opts := range services {
+		endpoint := scan.DetectMetricsEndpoint(servicePathOf(opts), opts.Framework)
+		if endpoint == "" {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("  - job_name: %s\n", imageName(opts)))
+		sb.WriteString(fmt.Sprintf("    metrics_path: %s\n", endpoint))
+		sb.WriteString("    static_configs:\n")
+		sb.WriteString(fmt.Sprintf("      - targets: ['%s:%s']\n", imageName(opts), getOrDefault(opts.Port, DefaultPort)))
+	}
+	return sb.String()
+}
+
+// otelCollectorConfig sends the traces to Jaeger and exposes the metrics to Prometheus
+const otelCollectorConfig = `receivers:
+  otlp:
+    protocols:
+      grpc:
+        endpoint: 0.0.0.0:4317
+      http:
+        endpoint: 0.0.0.0:4318
+
+processors:
+  batch: {}
+
+exporters:
+  otlp/jaeger:
+    endpoint: jaeger:4317
+    tls:
+      insecure: true
+  prometheus:
+    endpoint: 0.0.0.0:8889
+
+service:
+  pipelines:
+    traces:
+      receivers: [otlp]
+      processors: [batch]
+      exporters: [otlp/jaeger]
+    metrics:
+      receivers: [otlp]
+      processors: [batch]
+      exporters: [prometheus]
+`
+
+// grafanaDatasources provisions Prometheus and Jaeger in Grafana
+const grafanaDatasources = `apiVersion: 1
+
+datasources:
+  - name: Prometheus
+    type: prometheus
+    url: http://prometheus:9090
+    isDefault: true
+  - name: Jaeger
+    type: jaeger
+    url: http://jaeger:16686
+`
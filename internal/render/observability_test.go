@@ -0,0 +1,73 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestObservabilityStack(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Unable to get current working directory: %v", err)
+	}
+	defer os.Chdir(oldWd)
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Unable to change directory: %v", err)
+	}
+
+	pom := "<project><artifactId>spring-boot-starter-actuator</artifactId><artifactId>micrometer-registry-prometheus</artifactId></project>"
+	if err := os.MkdirAll("api", 0755); err != nil {
+		t.Fatalf("Unable to create api: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join("api", "pom.xml"), []byte(pom), 0644); err != nil {
+		t.Fatalf("Unable to write pom.xml: %v", err)
+	}
+
+	serviceList := ServiceList{
+		OutputDir:     ".turbotilt",
+		Observability: ObservabilityOptions{Enabled: true},
+		Services: []Options{{
+			ServiceName: "api",
+			Framework:   "spring",
+			Port:        "8080",
+			Path:        "api",
+			OutputDir:   ".turbotilt",
+			Debug:       true,
+		}},
+	}
+	if err := GenerateMultiServiceCompose(serviceList); err != nil {
+		t.Fatalf("GenerateMultiServiceCompose returned an error: %v", err)
+	}
+
+	compose, err := os.ReadFile(filepath.Join(".turbotilt", "docker-compose.yml"))
+	if err != nil {
+		t.Fatalf("Unable to read docker-compose.yml: %v", err)
+	}
+	for _, expected := range []string{
+		"- JAVA_TOOL_OPTIONS=-javaagent:/otel/opentelemetry-javaagent.jar " + jdwpOptions(),
+		"- OTEL_SERVICE_NAME=api",
+		"- MANAGEMENT_ENDPOINTS_WEB_EXPOSURE_INCLUDE=health,info,prometheus",
+		"- otel_agent:/otel:ro",
+		"      otel-agent:\n        condition: service_completed_successfully",
+		"  otel-collector:\n",
+		"  jaeger:\n",
+		"  prometheus:\n",
+		"  grafana:\n",
+	} {
+		if !strings.Contains(string(compose), expected) {
+			t.Errorf("docker-compose.yml should contain %q:\n%s", expected, compose)
+		}
+	}
+
+	// Prometheus scrapes the actuator endpoint of the service
+	prometheus, err := os.ReadFile(filepath.Join(".turbotilt", "observability", "prometheus.yml"))
+	if err != nil {
+		t.Fatalf("Unable to read prometheus.yml: %v", err)
+	}
+	if !strings.Contains(string(prometheus), "metrics_path: /actuator/prometheus\n    static_configs:\n      - targets: ['api:8080']") {
+		t.Errorf("prometheus.yml should scrape the actuator endpoint of api:\n%s", prometheus)
+	}
+}
@@ -0,0 +1,122 @@
+package render
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// outputGitignore keeps the generated artifacts of the output directory out of version control,
+// except the customized templates and recipes
+const outputGitignore = "# Generated by turbotilt - artifacts of this directory are not versioned\n*\n!.gitignore\n!templates/\n!templates/**\n!recipes/\n!recipes/**\n"
+
+// outputPath returns the path of a generated file in the output directory
+func outputPath(outputDir string, name ...string) string {
+	return filepath.Join(append([]string{outputDir}, name...)...)
+}
+
+// relativeToOutput rewrites a path relative to the current directory so that it is relative
+// to the output directory, where the generated files referencing it are written
+func relativeToOutput(outputDir, path string) string {
+	if outputDir == "" || filepath.Clean(outputDir) == "." {
+		return path
+	}
+
+	absOutput, err := filepath.Abs(outputDir)
+	if err != nil {
+		return path
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+
+	rel, err := filepath.Rel(absOutput, absPath)
+	if err != nil {
+		return path
+	}
+
+	rel = filepath.ToSlash(rel)
+	if !strings.HasPrefix(rel, ".") {
+		rel = "./" + rel
+	}
+	return rel
+}
+
+// serviceDirFromOutput returns the service directory relative to the output directory
+func serviceDirFromOutput(opts Options) string {
+	return filepath.ToSlash(relativeToOutput(opts.OutputDir, servicePathOf(opts)))
+}
+
+// DockerfilePath returns where the generated Dockerfile of a service is written: in the service
+// directory, or in the output directory (in a per-service folder for multi-service projects)
+func DockerfilePath(opts Options) string {
+	if opts.OutputDir == "" {
+		return filepath.Join(servicePathOf(opts), "Dockerfile")
+	}
+	if opts.ServiceName == "" {
+		return outputPath(opts.OutputDir, "Dockerfile")
+	}
+	return outputPath(opts.OutputDir, opts.ServiceName, "Dockerfile")
+}
+
+// composeDockerfile returns the generated Dockerfile relative to the build context of the service,
+// or an empty string when the Dockerfile is the one of the build context
+func composeDockerfile(opts Options) string {
+	if opts.OutputDir == "" || !UsesGeneratedDockerfile(opts) {
+		return ""
+	}
+	rel, err := filepath.Rel(servicePathOf(opts), DockerfilePath(opts))
+	if err != nil {
+		return ""
+	}
+	return filepath.ToSlash(rel)
+}
+
+// composeProjectName returns the Docker Compose project name of the current directory.
+// It is set explicitly as Compose would otherwise name every project after the output directory.
+func composeProjectName() string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "app"
+	}
+
+	var sb strings.Builder
+	for _, r := range strings.ToLower(filepath.Base(cwd)) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' || r == '_' {
+			sb.WriteRune(r)
+		}
+	}
+	if sb.Len() == 0 {
+		return "app"
+	}
+	return sb.String()
+}
+
+// ensureOutputDir creates the output directory and its .gitignore when they do not exist
+func ensureOutputDir(outputDir string) error {
+	if outputDir == "" || filepath.Clean(outputDir) == "." {
+		return nil
+	}
+
+	if err := outputFS.MkdirAll(outputDir, 0755); err != nil {
+		return err
+	}
+
+	// A .gitignore written by a previous version is updated, a hand-written one is kept
+	gitignore := filepath.Join(outputDir, ".gitignore")
+	content, err := outputFS.ReadFile(gitignore)
+	if os.IsNotExist(err) || (err == nil && strings.HasPrefix(string(content), GeneratedFileHeader) && string(content) != outputGitignore) {
+		return outputFS.WriteFile(gitignore, []byte(outputGitignore), 0644)
+	}
+	return nil
+}
+
+// writeOutputFile writes a generated file in the output directory
+func writeOutputFile(outputDir, name string, body []byte, force bool) error {
+	if err := ensureOutputDir(outputDir); err != nil {
+		return fmt.Errorf("error creating output directory: %w", err)
+	}
+	return writeGeneratedFile(outputPath(outputDir, name), body, force)
+}
@@ -0,0 +1,101 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"turbotilt/internal/scan"
+)
+
+func TestRelativeToOutput(t *testing.T) {
+	tests := []struct {
+		outputDir string
+		path      string
+		expected  string
+	}{
+		{"", "./orders", "./orders"},
+		{".", "./orders", "./orders"},
+		{".turbotilt", ".", ".."},
+		{".turbotilt", "./orders", "../orders"},
+		{".turbotilt", "orders/envs/local.env", "../orders/envs/local.env"},
+		{"build/turbotilt", ".", "../.."},
+		{".turbotilt", ".turbotilt/orders/Dockerfile", "./orders/Dockerfile"},
+	}
+
+	for _, tc := range tests {
+		if result := relativeToOutput(tc.outputDir, tc.path); result != tc.expected {
+			t.Errorf("relativeToOutput(%q, %q) = %q, expected %q", tc.outputDir, tc.path, result, tc.expected)
+		}
+	}
+}
+
+func TestGenerateInOutputDir(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Unable to get current working directory: %v", err)
+	}
+	defer os.Chdir(oldWd)
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Unable to change directory: %v", err)
+	}
+
+	if err := os.MkdirAll(filepath.Join("orders", "envs"), 0755); err != nil {
+		t.Fatalf("Unable to create service directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join("orders", "envs", "local.env"), []byte("A=1\n"), 0644); err != nil {
+		t.Fatalf("Unable to create environment file: %v", err)
+	}
+
+	opts := Options{
+		ServiceName:   "orders",
+		Framework:     "spring",
+		Port:          "8080",
+		JDKVersion:    "17",
+		Path:          "./orders",
+		BuildStrategy: scan.BuildGeneratedDockerfile,
+		OutputDir:     ".turbotilt",
+	}
+	serviceList := ServiceList{Services: []Options{opts}, OutputDir: ".turbotilt"}
+
+	if err := GenerateDockerfile(opts); err != nil {
+		t.Fatalf("GenerateDockerfile returned an error: %v", err)
+	}
+	if err := GenerateMultiServiceCompose(serviceList); err != nil {
+		t.Fatalf("GenerateMultiServiceCompose returned an error: %v", err)
+	}
+
+	// Nothing is written in the service directory
+	if _, err := os.Stat(filepath.Join("orders", "Dockerfile")); !os.IsNotExist(err) {
+		t.Error("The Dockerfile should not be generated in the service directory")
+	}
+	for _, file := range []string{"Dockerfile", "docker-compose.yml"} {
+		if _, err := os.Stat(file); !os.IsNotExist(err) {
+			t.Errorf("%s should not be generated in the project root", file)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(".turbotilt", "orders", "Dockerfile")); err != nil {
+		t.Errorf("The Dockerfile should be generated in the output directory: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(".turbotilt", ".gitignore")); err != nil {
+		t.Errorf("The output directory should be ignored by git: %v", err)
+	}
+
+	compose, err := os.ReadFile(filepath.Join(".turbotilt", "docker-compose.yml"))
+	if err != nil {
+		t.Fatalf("Unable to read docker-compose.yml: %v", err)
+	}
+	for _, expected := range []string{
+		"context: ../orders",
+		"dockerfile: ../.turbotilt/orders/Dockerfile",
+		"- ../orders/envs/local.env",
+		"- '../orders:/app'",
+	} {
+		if !strings.Contains(string(compose), expected) {
+			t.Errorf("docker-compose.yml should contain %q:\n%s", expected, compose)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package render
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// GeneratedFileHeader is the prefix of the first line of every file generated by Turbotilt
+const GeneratedFileHeader = "# Generated by turbotilt"
+
+// PlainFilesRecord records, in the directory of generated files that cannot carry the header
+// (JSON read by the tools), the hash of each of them by file name
+const PlainFilesRecord = ".turbotilt-generated.json"
+
+// FileOwnership describes the relationship between Turbotilt and a file on disk
+type FileOwnership int
+
+const (
+	FileMissing  FileOwnership = iota // The file does not exist
+	FileOwned                         // Generated by Turbotilt and unchanged since
+	FileModified                      // Generated by Turbotilt but edited since
+	FileForeign                       // Not generated by Turbotilt
+)
+
+// String returns a readable description of the ownership
+func (o FileOwnership) String() string {
+	switch o {
+	case FileMissing:
+		return "missing"
+	case FileOwned:
+		return "generated"
+	case FileModified:
+		return "modified"
+	default:
+		return "not generated by turbotilt"
+	}
+}
+
+// OverwriteError is returned when writing a file would destroy content Turbotilt does not own
+type OverwriteError struct {
+	Path      string
+	Ownership FileOwnership
+}
+
+func (e *OverwriteError) Error() string {
+	if e.Ownership == FileModified {
+		return fmt.Sprintf("%s was modified since it was generated (use --force to overwrite it)", e.Path)
+	}
+	return fmt.Sprintf("%s was not generated by turbotilt (use --force to overwrite it)", e.Path)
+}
+
+// stampContent prepends the Turbotilt header, including the hash of the body, to generated content
+func stampContent(body []byte) []byte {
+	header := fmt.Sprintf("%s - do not edit, run 'turbotilt init' instead (sha256:%s)\n", GeneratedFileHeader, contentHash(body))
+	return append([]byte(header), body...)
+}
+
+// contentHash returns the hexadecimal SHA-256 of the content
+func contentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// ownershipOf determines the ownership of a file from its content
+func ownershipOf(content []byte) FileOwnership {
+	header, body, found := bytes.Cut(content, []byte("\n"))
+	if !found || !strings.HasPrefix(string(header), GeneratedFileHeader) {
+		return FileForeign
+	}
+
+	line := string(header)
+	start := strings.LastIndex(line, "(sha256:")
+	if start < 0 || !strings.HasSuffix(line, ")") {
+		return FileModified
+	}
+
+	if line[start+len("(sha256:"):len(line)-1] != contentHash(body) {
+		return FileModified
+	}
+	return FileOwned
+}
+
+// CheckOwnership returns the ownership of the file at path
+func CheckOwnership(path string) (FileOwnership, error) {
+	content, err := outputFS.ReadFile(path)
+	if os.IsNotExist(err) {
+		return FileMissing, nil
+	}
+	if err != nil {
+		return FileForeign, err
+	}
+
+	ownership := ownershipOf(content)
+	if ownership == FileForeign {
+		// Files without header are generated when their hash is recorded next to them
+		if hash, ok := readPlainFilesRecord(filepath.Dir(path))[filepath.Base(path)]; ok {
+			if hash == contentHash(content) {
+				return FileOwned, nil
+			}
+			return FileModified, nil
+		}
+	}
+	return ownership, nil
+}
+
+// writeGeneratedFile stamps and writes generated content to path.
+// Files not generated by Turbotilt, or edited since, are only overwritten when force is set.
+func writeGeneratedFile(path string, body []byte, force bool) error {
+	if err := checkOverwrite(path, force); err != nil {
+		return err
+	}
+	return writeFile(path, stampContent(body))
+}
+
+// writeGeneratedPlainFile writes generated content that cannot carry the header (JSON read by the tools),
+// its hash being recorded in the PlainFilesRecord of its directory. It is protected as the stamped files.
+func writeGeneratedPlainFile(path string, body []byte, force bool) error {
+	if err := checkOverwrite(path, force); err != nil {
+		return err
+	}
+	if err := writeFile(path, body); err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	record := readPlainFilesRecord(dir)
+	record[filepath.Base(path)] = contentHash(body)
+	content, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(filepath.Join(dir, PlainFilesRecord), append(content, '\n'))
+}
+
+// checkOverwrite fails when the file at path was not generated by Turbotilt, or edited since,
+// unless force is set. A preview only flags the file.
+func checkOverwrite(path string, force bool) error {
+	ownership, err := CheckOwnership(path)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", path, err)
+	}
+
+	if !force && (ownership == FileForeign || ownership == FileModified) {
+		preview, ok := outputFS.(previewFileSystem)
+		if !ok {
+			return &OverwriteError{Path: path, Ownership: ownership}
+		}
+		// A preview shows what would change, the file is only flagged
+		preview.requireForce(path, ownership)
+	}
+	return nil
+}
+
+// writeFile writes content to path, creating its directory
+func writeFile(path string, content []byte) error {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := outputFS.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("error creating directory %s: %w", dir, err)
+		}
+	}
+
+	if err := outputFS.WriteFile(path, content, 0644); err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+	return nil
+}
+
+// readPlainFilesRecord returns the hashes of the generated files without header of a directory,
+// by file name. A missing or invalid record is empty.
+func readPlainFilesRecord(dir string) map[string]string {
+	record := make(map[string]string)
+	content, err := outputFS.ReadFile(filepath.Join(dir, PlainFilesRecord))
+	if err != nil {
+		return record
+	}
+	_ = json.Unmarshal(content, &record)
+	return record
+}
+
+// CleanResult contains the outcome of CleanGeneratedFiles
+type CleanResult struct {
+	Removed []string                 // Files removed
+	Kept    map[string]FileOwnership // Files left untouched and why
+}
+
+// CleanGeneratedFiles removes the given files when they were generated by Turbotilt and not edited since.
+// Missing files are ignored, the other ones are kept and reported.
+func CleanGeneratedFiles(paths []string) (CleanResult, error) {
+	result := CleanResult{Kept: make(map[string]FileOwnership)}
+
+	for _, path := range paths {
+		ownership, err := CheckOwnership(path)
+		if err != nil {
+			return result, fmt.Errorf("error reading %s: %w", path, err)
+		}
+
+		switch ownership {
+		case FileMissing:
+			continue
+		case FileOwned:
+			if err := os.Remove(path); err != nil {
+				return result, fmt.Errorf("error removing %s: %w", path, err)
+			}
+			result.Removed = append(result.Removed, path)
+		default:
+			result.Kept[path] = ownership
+		}
+	}
+
+	return result, nil
+}
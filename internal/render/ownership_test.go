@@ -0,0 +1,113 @@
+package render
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckOwnership(t *testing.T) {
+	tempDir := t.TempDir()
+
+	generated := filepath.Join(tempDir, "generated")
+	if err := os.WriteFile(generated, stampContent([]byte("services: {}\n")), 0644); err != nil {
+		t.Fatalf("Unable to create file: %v", err)
+	}
+
+	modified := filepath.Join(tempDir, "modified")
+	content := append(stampContent([]byte("services: {}\n")), []byte("# edited\n")...)
+	if err := os.WriteFile(modified, content, 0644); err != nil {
+		t.Fatalf("Unable to create file: %v", err)
+	}
+
+	foreign := filepath.Join(tempDir, "foreign")
+	if err := os.WriteFile(foreign, []byte("FROM alpine\n"), 0644); err != nil {
+		t.Fatalf("Unable to create file: %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want FileOwnership
+	}{
+		{generated, FileOwned},
+		{modified, FileModified},
+		{foreign, FileForeign},
+		{filepath.Join(tempDir, "missing"), FileMissing},
+	}
+
+	for _, tt := range tests {
+		got, err := CheckOwnership(tt.path)
+		if err != nil {
+			t.Fatalf("CheckOwnership(%s) returned an error: %v", tt.path, err)
+		}
+		if got != tt.want {
+			t.Errorf("CheckOwnership(%s) = %s, want %s", filepath.Base(tt.path), got, tt.want)
+		}
+	}
+}
+
+func TestWriteGeneratedFileProtection(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Tiltfile")
+
+	// A missing file is created and stamped
+	if err := writeGeneratedFile(path, []byte("docker_compose('docker-compose.yml')\n"), false); err != nil {
+		t.Fatalf("writeGeneratedFile returned an error: %v", err)
+	}
+	content, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(content), GeneratedFileHeader) {
+		t.Errorf("The generated file should start with the Turbotilt header:\n%s", content)
+	}
+
+	// An unmodified generated file is regenerated
+	if err := writeGeneratedFile(path, []byte("# v2\n"), false); err != nil {
+		t.Fatalf("Regenerating an unmodified file returned an error: %v", err)
+	}
+
+	// An edited file is protected
+	if err := os.WriteFile(path, append(content, []byte("local('echo hi')\n")...), 0644); err != nil {
+		t.Fatalf("Unable to edit file: %v", err)
+	}
+	err := writeGeneratedFile(path, []byte("# v3\n"), false)
+	var overwriteErr *OverwriteError
+	if !errors.As(err, &overwriteErr) || overwriteErr.Ownership != FileModified {
+		t.Fatalf("Expected an OverwriteError for a modified file, got %v", err)
+	}
+
+	// --force overwrites it
+	if err := writeGeneratedFile(path, []byte("# v3\n"), true); err != nil {
+		t.Fatalf("Forced write returned an error: %v", err)
+	}
+	if ownership, _ := CheckOwnership(path); ownership != FileOwned {
+		t.Errorf("The forced file should be owned, got %s", ownership)
+	}
+}
+
+func TestCleanGeneratedFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	owned := filepath.Join(tempDir, "docker-compose.yml")
+	foreign := filepath.Join(tempDir, "Dockerfile")
+
+	if err := writeGeneratedFile(owned, []byte("services: {}\n"), false); err != nil {
+		t.Fatalf("writeGeneratedFile returned an error: %v", err)
+	}
+	if err := os.WriteFile(foreign, []byte("FROM alpine\n"), 0644); err != nil {
+		t.Fatalf("Unable to create file: %v", err)
+	}
+
+	result, err := CleanGeneratedFiles([]string{owned, foreign, filepath.Join(tempDir, "Tiltfile")})
+	if err != nil {
+		t.Fatalf("CleanGeneratedFiles returned an error: %v", err)
+	}
+
+	if len(result.Removed) != 1 || result.Removed[0] != owned {
+		t.Errorf("Only the generated file should be removed, got %v", result.Removed)
+	}
+	if result.Kept[foreign] != FileForeign {
+		t.Errorf("The hand-written Dockerfile should be kept, got %v", result.Kept)
+	}
+	if _, err := os.Stat(foreign); err != nil {
+		t.Errorf("The hand-written Dockerfile was removed: %v", err)
+	}
+}
@@ -0,0 +1,678 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+
+	"turbotilt/internal/scan"
+	"turbotilt/recipes"
+)
+
+// ProjectRecipesDir is the directory of the dependency recipes of a project, in its output directory
+var ProjectRecipesDir = filepath.Join(".turbotilt", "recipes")
+
+// SetProjectRecipesDir places the project recipes in the output directory
+func SetProjectRecipesDir(outputDir string) {
+	ProjectRecipesDir = filepath.Join(outputDir, "recipes")
+}
+
+// loadedCatalog is the catalog last loaded, reused as long as the recipe files are unchanged
+var loadedCatalog struct {
+	key     string
+	catalog *RecipeCatalog
+	err     error
+}
+
+// Recipe describes how a dependent service (database, broker...) is added to the environment.
+// Healthcheck and connection values are templates over RecipeData, e.g. {{.Env.POSTGRES_DB}}.
+type Recipe struct {
+	Name        string                       `yaml:"name"`                  // Service type and compose service name
+	Aliases     []string                     `yaml:"aliases,omitempty"`     // Other service types using the recipe
+	Image       string                       `yaml:"image"`                 // Image reference, without tag
+	Version     string                       `yaml:"version,omitempty"`     // Image tag used when the service sets none
+	Port        string                       `yaml:"port,omitempty"`        // Container port, published on the service port
+	HostPort    string                       `yaml:"hostPort,omitempty"`    // Host port when the service sets none (the container port by default)
+	ExtraPorts  []string                     `yaml:"extraPorts,omitempty"`  // Other container ports, published on the same host ports
+	Command     string                       `yaml:"command,omitempty"`     // Command overriding the one of the image
+	Env         map[string]string            `yaml:"env,omitempty"`         // Default environment, overridden by the credentials
+	Volumes     []string                     `yaml:"volumes,omitempty"`     // Mounts, named volumes are declared in the compose file
+	Healthcheck *RecipeHealthcheck           `yaml:"healthcheck,omitempty"` // Readiness the application services wait for
+	Connection  map[string]map[string]string `yaml:"connection,omitempty"`  // Environment of the application services, by framework or "default"
+	Address     string                       `yaml:"address,omitempty"`     // host:port the other containers connect to ({{.Host}}:{{.Port}} by default)
+	Mounts      []RecipeMount                `yaml:"mounts,omitempty"`      // Project files of the service mounted when found (realms, definitions...)
+	Init        *RecipeInit                  `yaml:"init,omitempty"`        // One-shot service creating the resources of the service
+	SeedDir     string                       `yaml:"seedDir,omitempty"`     // Directory of the container receiving the seed files
+	Client      string                       `yaml:"client,omitempty"`      // Command running the SQL statements of its standard input (seed scripts)
+	Requires    []string                     `yaml:"requires,omitempty"`    // Recipes started with this one
+
+	Source string `yaml:"-"` // Path of the recipe file, or "built-in"
+}
+
+// RecipeHealthcheck is the compose healthcheck of a recipe
+type RecipeHealthcheck struct {
+	Test     string `yaml:"test"` // Shell command, successful when the service is ready
+	Interval string `yaml:"interval,omitempty"`
+	Timeout  string `yaml:"timeout,omitempty"`
+	Retries  int    `yaml:"retries,omitempty"`
+}
+
+// RecipeInit is a one-shot service run once the service is started, when resources are declared
+// (buckets, queues...). The application services wait for its success.
+type RecipeInit struct {
+	Image   string `yaml:"image"`
+	Script  string `yaml:"script"`            // Shell script, template over RecipeData
+	SeedDir string `yaml:"seedDir,omitempty"` // Directory of the container receiving the seed files
+}
+
+// RecipeMount mounts project files found in the directory of a dependent service, such as realm exports
+// or a config repository. Mounts whose target is already mounted by the volumes of the service are skipped.
+type RecipeMount struct {
+	Name     string            `yaml:"name"`               // Name of the mount, whose source the manifest can set in 'mounts'
+	Files    []string          `yaml:"files,omitempty"`    // Files mounted (globs), or the files a directory of dirs must contain
+	Dirs     []string          `yaml:"dirs,omitempty"`     // Directories looked up in order, the first one containing files is mounted
+	Contains string            `yaml:"contains,omitempty"` // Text the mounted files must contain
+	Target   string            `yaml:"target"`             // Path in the container, a directory receiving each file when ending with /
+	Env      map[string]string `yaml:"env,omitempty"`      // Environment set when mounted, templates receiving the Mounted files
+	Init     bool              `yaml:"init,omitempty"`     // Mounted in the init service, which then runs with the Mounted files
+}
+
+// RecipeData is the data of the recipe templates
+type RecipeData struct {
+	Name      string            // Compose service name
+	Host      string            // Hostname of the service in the compose network
+	Port      string            // Container port
+	HostPort  string            // Port published on the host
+	Version   string            // Image tag
+	Env       map[string]string // Environment of the service, credentials included
+	Resources []string          // Resources created at startup: buckets, or kind:name (queue:orders...)
+	Seeds     []string          // File names of the seed files, in the seed directory
+	Mounted   []string          // Project files mounted by a mount (mount env) or by the mounts of the init service (init script)
+}
+
+// ResourcesOf returns the names of the resources of a kind (bucket, queue, topic, table...),
+// the resources without a kind being buckets
+func (d RecipeData) ResourcesOf(kind string) []string {
+	var names []string
+	for _, resource := range d.Resources {
+		resourceKind, name, found := strings.Cut(resource, ":")
+		if !found {
+			resourceKind, name = "bucket", resource
+		}
+		if resourceKind == kind {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// RecipeCatalog contains the recipes by service type
+type RecipeCatalog struct {
+	recipes map[string]*Recipe
+	aliases map[string]string
+}
+
+// UserRecipesDir returns the directory of the recipes of the user (~/.config/turbotilt/recipes)
+func UserRecipesDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "turbotilt", "recipes")
+}
+
+// RecipeSearchPath returns the directories of recipes, by priority: project recipes first, then user recipes
+func RecipeSearchPath() []string {
+	dirs := []string{ProjectRecipesDir}
+	if userDir := UserRecipesDir(); userDir != "" {
+		dirs = append(dirs, userDir)
+	}
+	return dirs
+}
+
+// LoadRecipes loads the shipped recipes, overridden by the ones of the search path.
+// The catalog is loaded once and reloaded only when the recipe files change.
+func LoadRecipes() (*RecipeCatalog, error) {
+	dirs := RecipeSearchPath()
+	key := recipeFilesKey(dirs)
+	if loadedCatalog.key == key && (loadedCatalog.catalog != nil || loadedCatalog.err != nil) {
+		return loadedCatalog.catalog, loadedCatalog.err
+	}
+
+	catalog, err := loadRecipes(dirs)
+	loadedCatalog.key, loadedCatalog.catalog, loadedCatalog.err = key, catalog, err
+	return catalog, err
+}
+
+// recipeFilesKey identifies the recipe files of the search path by their path, size and modification time
+func recipeFilesKey(dirs []string) string {
+	var key strings.Builder
+	for _, dir := range dirs {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			abs = dir
+		}
+		key.WriteString(abs + "\n")
+		entries, _ := os.ReadDir(dir)
+		for _, entry := range entries {
+			if info, err := entry.Info(); err == nil {
+				fmt.Fprintf(&key, "%s %d %d\n", entry.Name(), info.Size(), info.ModTime().UnixNano())
+			}
+		}
+	}
+	return key.String()
+}
+
+// loadRecipes loads the shipped recipes, overridden by the ones of the directories
+func loadRecipes(dirs []string) (*RecipeCatalog, error) {
+	catalog := &RecipeCatalog{recipes: make(map[string]*Recipe), aliases: make(map[string]string)}
+	if err := catalog.load(recipes.FS, "built-in"); err != nil {
+		return nil, err
+	}
+
+	// The directories of lower priority are loaded first, so that the others override them
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if _, err := os.Stat(dirs[i]); err != nil {
+			continue
+		}
+		if err := catalog.load(os.DirFS(dirs[i]), dirs[i]); err != nil {
+			return nil, err
+		}
+	}
+	return catalog, nil
+}
+
+// load adds the recipe files of a directory to the catalog
+func (c *RecipeCatalog) load(fsys fs.FS, source string) error {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return fmt.Errorf("error reading recipes of %s: %w", source, err)
+	}
+
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+
+		path := entry.Name()
+		if source != "built-in" {
+			path = filepath.Join(source, entry.Name())
+		}
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return fmt.Errorf("error reading recipe %s: %w", path, err)
+		}
+		recipe, err := parseRecipe(content)
+		if err != nil {
+			return fmt.Errorf("recipe %s: %w", path, err)
+		}
+		recipe.Source = source
+		if source != "built-in" {
+			recipe.Source = path
+		}
+		c.add(recipe)
+	}
+	return nil
+}
+
+// add adds a recipe to the catalog, replacing the recipe of the same name
+func (c *RecipeCatalog) add(recipe *Recipe) {
+	name := strings.ToLower(recipe.Name)
+	c.recipes[name] = recipe
+	delete(c.aliases, name)
+	for _, alias := range recipe.Aliases {
+		c.aliases[strings.ToLower(alias)] = name
+	}
+}
+
+// parseRecipe decodes a recipe and checks its fields and templates
+func parseRecipe(content []byte) (*Recipe, error) {
+	var recipe Recipe
+	if err := yaml.Unmarshal(content, &recipe); err != nil {
+		return nil, err
+	}
+	if recipe.Name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	if recipe.Image == "" {
+		return nil, fmt.Errorf("image is required")
+	}
+
+	var templates []string
+	if recipe.Healthcheck != nil {
+		templates = append(templates, recipe.Healthcheck.Test)
+	}
+	if recipe.Client != "" {
+		templates = append(templates, recipe.Client)
+	}
+	if recipe.Init != nil {
+		if recipe.Init.Image == "" {
+			return nil, fmt.Errorf("init.image is required")
+		}
+		templates = append(templates, recipe.Init.Script)
+	}
+	for _, value := range recipe.Env {
+		templates = append(templates, value)
+	}
+	for _, env := range recipe.Connection {
+		for _, value := range env {
+			templates = append(templates, value)
+		}
+	}
+	for _, mount := range recipe.Mounts {
+		if mount.Name == "" || mount.Target == "" {
+			return nil, fmt.Errorf("mounts: name and target are required")
+		}
+		if mount.Init && recipe.Init == nil {
+			return nil, fmt.Errorf("mounts: %s is mounted in the init service, which is not defined", mount.Name)
+		}
+		for _, value := range mount.Env {
+			templates = append(templates, value)
+		}
+	}
+	for _, text := range templates {
+		if _, err := template.New(recipe.Name).Funcs(recipeFuncs).Parse(text); err != nil {
+			return nil, err
+		}
+	}
+	return &recipe, nil
+}
+
+// Lookup returns the recipe of a service type, by name or alias
+func (c *RecipeCatalog) Lookup(serviceType string) (*Recipe, bool) {
+	name := strings.ToLower(serviceType)
+	if recipe, ok := c.recipes[name]; ok {
+		return recipe, true
+	}
+	if target, ok := c.aliases[name]; ok {
+		return c.recipes[target], true
+	}
+	return nil, false
+}
+
+// Recipes returns the recipes of the catalog in alphabetical order
+func (c *RecipeCatalog) Recipes() []*Recipe {
+	list := make([]*Recipe, 0, len(c.recipes))
+	for _, name := range sortedKeys(c.recipes) {
+		list = append(list, c.recipes[name])
+	}
+	return list
+}
+
+// EjectRecipe copies a shipped recipe to the project recipes directory, where it overrides
+// the shipped one. An existing project recipe is only overwritten when force is set.
+func EjectRecipe(name string, force bool) (string, error) {
+	name = strings.TrimSuffix(strings.ToLower(name), ".yaml")
+	content, err := fs.ReadFile(recipes.FS, name+".yaml")
+	if err != nil {
+		return "", fmt.Errorf("unknown recipe %s (see 'turbotilt recipes list')", name)
+	}
+
+	path := filepath.Join(ProjectRecipesDir, name+".yaml")
+	if _, err := outputFS.ReadFile(path); err == nil && !force {
+		return "", fmt.Errorf("%s already exists (use --force to overwrite it)", path)
+	}
+
+	if err := outputFS.MkdirAll(ProjectRecipesDir, 0755); err != nil {
+		return "", fmt.Errorf("error creating directory %s: %w", ProjectRecipesDir, err)
+	}
+	if err := outputFS.WriteFile(path, content, 0644); err != nil {
+		return "", fmt.Errorf("error writing %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// IsKnownServiceType checks if a recipe exists for a service type
+func IsKnownServiceType(serviceType string) bool {
+	catalog, err := LoadRecipes()
+	if err != nil {
+		return false
+	}
+	_, ok := catalog.Lookup(serviceType)
+	return ok
+}
+
+// CanonicalServiceType returns the name of the recipe of a service type given by name or alias,
+// the lower-case service type when no recipe matches
+func CanonicalServiceType(serviceType string) string {
+	if recipe, ok := defaultCatalog().Lookup(serviceType); ok {
+		return recipe.Name
+	}
+	return strings.ToLower(serviceType)
+}
+
+// defaultCatalog returns the catalog of the current directory, or the shipped recipes when
+// a user or project recipe is invalid (reported by the manifest validation and 'turbotilt recipes')
+func defaultCatalog() *RecipeCatalog {
+	if catalog, err := LoadRecipes(); err == nil {
+		return catalog
+	}
+	catalog := &RecipeCatalog{recipes: make(map[string]*Recipe), aliases: make(map[string]string)}
+	_ = catalog.load(recipes.FS, "built-in")
+	return catalog
+}
+
+// definition builds the compose definition of the recipe for a service
+func (r *Recipe) definition(service scan.ServiceConfig) ComposeServiceDefinition {
+	env := make(map[string]string, len(r.Env)+len(service.Credentials))
+	for key, value := range r.Env {
+		env[key] = value
+	}
+	for key, value := range service.Credentials {
+		if value != "" {
+			env[key] = value
+		}
+	}
+
+	definition := ComposeServiceDefinition{
+		Name:        r.Name,
+		Image:       fmt.Sprintf("%s:%s", r.Image, getOrDefault(service.Version, getOrDefault(r.Version, "latest"))),
+		Command:     r.Command,
+		Environment: env,
+		Volumes:     append([]string(nil), r.Volumes...),
+		DependsOn:   r.Requires,
+	}
+	if r.Port != "" {
+		definition.Port = fmt.Sprintf("%s:%s", getOrDefault(service.Port, getOrDefault(r.HostPort, r.Port)), r.Port)
+	}
+	for _, port := range r.ExtraPorts {
+		definition.ExtraPorts = append(definition.ExtraPorts, port+":"+port)
+	}
+	// The environment may refer to the published port, e.g. the address advertised to the host
+	for key, value := range env {
+		if strings.Contains(value, "{{") {
+			env[key] = r.expand(value, definition, service)
+		}
+	}
+	// The mounted project files set their environment, unless the service sets it
+	_, mountEnv, _ := r.projectMounts(service, definition, false)
+	for key, value := range mountEnv {
+		if service.Credentials[key] == "" && value != "" {
+			env[key] = value
+		}
+	}
+	if r.Healthcheck != nil {
+		healthcheck := *r.Healthcheck
+		healthcheck.Test = r.expand(healthcheck.Test, definition, service)
+		definition.Healthcheck = &healthcheck
+	}
+	return definition
+}
+
+// initDefinition returns the one-shot service creating the resources of a service, loading its seed
+// files or the project files of its init mounts, if any, with the volumes of these files
+func (r *Recipe) initDefinition(service scan.ServiceConfig, definition ComposeServiceDefinition) (ComposeServiceDefinition, bool) {
+	if r.Init == nil {
+		return ComposeServiceDefinition{}, false
+	}
+	volumes, _, mounted := r.projectMounts(service, definition, true)
+	seeds := r.Init.SeedDir != "" && len(service.Seeds) > 0
+	if len(service.Resources) == 0 && !seeds && len(mounted) == 0 {
+		return ComposeServiceDefinition{}, false
+	}
+	// Resources of other kinds leave nothing to create
+	data := r.data(definition, service)
+	data.Mounted = mounted
+	script := strings.TrimSpace(r.render(r.Init.Script, data))
+	if script == "" {
+		return ComposeServiceDefinition{}, false
+	}
+	if seeds {
+		volumes = append(volumes, seedMounts(service.Seeds, r.Init.SeedDir)...)
+	}
+	return ComposeServiceDefinition{
+		Name:      r.Name + "-init",
+		Image:     r.Init.Image,
+		Script:    script,
+		Volumes:   volumes,
+		DependsOn: []string{r.Name},
+		OneShot:   true,
+	}, true
+}
+
+// connectionEnv returns the environment connecting an application of the framework to the service
+func (r *Recipe) connectionEnv(framework string, service ComposeServiceDefinition) map[string]string {
+	return r.connectionEnvVia(framework, service, service.Name, r.Port)
+}
+
+// connectionEnvVia returns the environment connecting an application of the framework to the service
+// through another host and port, such as a proxy
+func (r *Recipe) connectionEnvVia(framework string, service ComposeServiceDefinition, host, port string) map[string]string {
+	data := r.data(service, scan.ServiceConfig{})
+	data.Host = host
+	data.Port = port
+
+	env := make(map[string]string)
+	for _, section := range []string{"default", framework} {
+		for key, value := range r.Connection[section] {
+			env[key] = r.render(value, data)
+		}
+	}
+	return env
+}
+
+// address returns the host:port the other containers of the environment connect to
+func (r *Recipe) address(service ComposeServiceDefinition) string {
+	if r.Address == "" {
+		return service.Name + ":" + r.Port
+	}
+	return r.expand(r.Address, service, scan.ServiceConfig{})
+}
+
+// proxiable reports whether the applications reach the service on its port, so that their
+// connection can go through a proxy
+func (r *Recipe) proxiable() bool {
+	if r.Port == "" {
+		return false
+	}
+	for _, env := range r.Connection {
+		for _, value := range env {
+			if strings.Contains(value, ".Port") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// expand renders a template of the recipe for the compose definition of a service, returning it
+// unchanged when it fails
+func (r *Recipe) expand(text string, service ComposeServiceDefinition, config scan.ServiceConfig) string {
+	return r.render(text, r.data(service, config))
+}
+
+// data returns the data of the templates of the recipe for the compose definition of a service
+func (r *Recipe) data(service ComposeServiceDefinition, config scan.ServiceConfig) RecipeData {
+	return RecipeData{
+		Name:      service.Name,
+		Host:      service.Name,
+		Port:      r.Port,
+		HostPort:  strings.SplitN(service.Port, ":", 2)[0],
+		Version:   strings.TrimPrefix(service.Image, r.Image+":"),
+		Env:       service.Environment,
+		Resources: config.Resources,
+		Seeds:     seedNames(config.Seeds),
+	}
+}
+
+// render renders a template of the recipe, returning it unchanged when it fails
+func (r *Recipe) render(text string, data RecipeData) string {
+	tmpl, err := template.New(r.Name).Funcs(recipeFuncs).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return text
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return text
+	}
+	return buf.String()
+}
+
+// recipeFuncs are the functions of the recipe templates
+var recipeFuncs = template.FuncMap{
+	// field returns the n-th field of a value split on a separator, or an empty string when it has fewer,
+	// e.g. the password of NEO4J_AUTH=neo4j/secret
+	"field": func(value, separator string, n int) string {
+		fields := strings.Split(value, separator)
+		if n < 0 || n >= len(fields) {
+			return ""
+		}
+		return fields[n]
+	},
+	// jsonField returns a top-level string field of a JSON file, e.g. the name of a realm export
+	"jsonField": func(path, field string) string {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return ""
+		}
+		var document map[string]interface{}
+		if err := json.Unmarshal(content, &document); err != nil {
+			return ""
+		}
+		value, _ := document[field].(string)
+		return value
+	},
+}
+
+// projectMounts returns the bind mounts of the project files found by the mounts of the recipe in the
+// directory of a service, sources relative to the current directory, the environment they set and the
+// files mounted. init selects the mounts of the init service instead of the ones of the service.
+func (r *Recipe) projectMounts(service scan.ServiceConfig, definition ComposeServiceDefinition, init bool) ([]string, map[string]string, []string) {
+	mounted := make(map[string]bool)
+	for _, volume := range service.Volumes {
+		if _, target, found := strings.Cut(volume, ":"); found {
+			target, _, _ = strings.Cut(target, ":")
+			mounted[strings.TrimSuffix(target, "/")] = true
+		}
+	}
+
+	var volumes, files []string
+	env := make(map[string]string)
+	for _, mount := range r.Mounts {
+		if mount.Init != init || mounted[strings.TrimSuffix(mount.Target, "/")] {
+			continue
+		}
+		sources := mount.sources(service)
+		if len(sources) == 0 {
+			continue
+		}
+		if !strings.HasSuffix(mount.Target, "/") {
+			// A single file or directory is mounted on the target
+			sources = sources[:1]
+		}
+		for _, source := range sources {
+			target := mount.Target
+			if strings.HasSuffix(target, "/") {
+				target += filepath.Base(source)
+			}
+			volumes = append(volumes, bindSource(source)+":"+target+":ro")
+		}
+
+		files = append(files, sources...)
+
+		data := r.data(definition, service)
+		data.Mounted = sources
+		for key, value := range mount.Env {
+			env[key] = r.render(value, data)
+		}
+	}
+	return volumes, env, files
+}
+
+// sources returns the project files or the directory mounted by a mount for a service: the source set
+// in the manifest, or the ones found in the directory of the service
+func (m RecipeMount) sources(service scan.ServiceConfig) []string {
+	if source := service.Mounts[m.Name]; source != "" {
+		return []string{source}
+	}
+	if service.Dir == "" {
+		return nil
+	}
+
+	if len(m.Dirs) > 0 {
+		for _, dir := range m.Dirs {
+			dir = filepath.Join(service.Dir, dir)
+			// A directory with a build file is an application, not a directory of project files
+			if scan.DetectBuildSystem(dir) == "" && len(m.files(dir)) > 0 {
+				return []string{dir}
+			}
+		}
+		return nil
+	}
+	return m.files(service.Dir)
+}
+
+// files returns the files of a directory matching the patterns of a mount, in the order of the patterns
+func (m RecipeMount) files(dir string) []string {
+	seen := make(map[string]bool)
+	var files []string
+	for _, pattern := range m.Files {
+		matches, _ := filepath.Glob(filepath.Join(dir, pattern))
+		for _, match := range matches {
+			if seen[match] {
+				continue
+			}
+			if m.Contains != "" {
+				content, err := os.ReadFile(match)
+				if err != nil || !strings.Contains(string(content), m.Contains) {
+					continue
+				}
+			}
+			seen[match] = true
+			files = append(files, match)
+		}
+	}
+	return files
+}
+
+// bindSource returns the source of a bind mount, prefixed with ./ so that a relative directory
+// is not taken for a named volume
+func bindSource(source string) string {
+	source = filepath.ToSlash(source)
+	if !filepath.IsAbs(source) && !strings.HasPrefix(source, ".") {
+		source = "./" + source
+	}
+	return source
+}
+
+// seedNames returns the file names of the seed files, as mounted in the seed directories
+func seedNames(seeds []string) []string {
+	var names []string
+	for _, seed := range seeds {
+		names = append(names, filepath.Base(seed))
+	}
+	return names
+}
+
+// seedMounts returns the mounts of the seed files in a seed directory
+func seedMounts(seeds []string, dir string) []string {
+	var mounts []string
+	for _, seed := range seeds {
+		mounts = append(mounts, seed+":"+path.Join(dir, filepath.Base(seed))+":ro")
+	}
+	return mounts
+}
+
+// namedVolumes returns the named volumes mounted by the recipe, which must be declared in the compose file
+func (r *Recipe) namedVolumes() []string {
+	var names []string
+	for _, volume := range r.Volumes {
+		source, _, found := strings.Cut(volume, ":")
+		if found && !strings.ContainsAny(source, "./~$") {
+			names = append(names, source)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
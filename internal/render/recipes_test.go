@@ -0,0 +1,474 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"turbotilt/internal/scan"
+)
+
+func TestRecipeCatalog(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Unable to get current working directory: %v", err)
+	}
+	defer os.Chdir(oldWd)
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Unable to change directory: %v", err)
+	}
+	t.Setenv("HOME", tempDir)
+
+	catalog, err := LoadRecipes()
+	if err != nil {
+		t.Fatalf("LoadRecipes returned an error: %v", err)
+	}
+	for _, serviceType := range []string{"mysql", "postgres", "postgresql", "mongodb", "redis", "kafka", "rabbitmq", "elasticsearch",
+		"keycloak", "minio", "mailpit", "mailhog", "mariadb", "sqlserver", "oracle", "oracle-xe", "cassandra", "neo4j", "localstack", "aws"} {
+		if _, ok := catalog.Lookup(serviceType); !ok {
+			t.Errorf("No built-in recipe for %s", serviceType)
+		}
+	}
+
+	// A project recipe adds a service type and overrides a shipped recipe
+	recipes := map[string]string{
+		"minio.yaml": `name: minio
+image: minio/minio
+version: latest
+port: "9000"
+command: server /data
+env:
+  MINIO_ROOT_USER: minio
+volumes:
+  - minio_data:/data
+connection:
+  spring:
+    S3_ENDPOINT: http://{{.Host}}:{{.Port}}
+    S3_ACCESS_KEY: "{{.Env.MINIO_ROOT_USER}}"
+`,
+		"redis.yaml": "name: redis\nimage: valkey/valkey\nversion: \"8\"\nport: \"6379\"\n",
+	}
+	if err := os.MkdirAll(ProjectRecipesDir, 0755); err != nil {
+		t.Fatalf("Unable to create the project recipes: %v", err)
+	}
+	for name, content := range recipes {
+		if err := os.WriteFile(filepath.Join(ProjectRecipesDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Unable to write %s: %v", name, err)
+		}
+	}
+	if !IsKnownServiceType("minio") {
+		t.Error("minio should be a known service type")
+	}
+
+	opts := Options{
+		ServiceName: "api",
+		Framework:   "spring",
+		Port:        "8080",
+		Path:        ".",
+		Services: []scan.ServiceConfig{
+			{Type: "minio", Credentials: map[string]string{"MINIO_ROOT_USER": "admin"}},
+			{Type: scan.Redis},
+			{Type: "postgresql", Port: "5433"},
+		},
+	}
+	if err := GenerateComposeWithServices(opts); err != nil {
+		t.Fatalf("GenerateComposeWithServices returned an error: %v", err)
+	}
+
+	compose, err := os.ReadFile("docker-compose.yml")
+	if err != nil {
+		t.Fatalf("Unable to read docker-compose.yml: %v", err)
+	}
+	for _, expected := range []string{
+		"  minio:\n    image: minio/minio:latest\n    command: server /data\n",
+		"- MINIO_ROOT_USER=admin",
+		"  minio_data:\n",
+		"- S3_ENDPOINT=http://minio:9000",
+		"- S3_ACCESS_KEY=admin",
+		"  redis:\n    image: valkey/valkey:8\n",
+		"- '5433:5432'",
+		"- SPRING_DATASOURCE_URL=jdbc:postgresql://postgres:5432/app",
+		"      postgres:\n        condition: service_healthy\n",
+		"      minio:\n        condition: service_started\n",
+		"      test: \"pg_isready -U postgres -d app\"\n",
+	} {
+		if !strings.Contains(string(compose), expected) {
+			t.Errorf("docker-compose.yml should contain %q:\n%s", expected, compose)
+		}
+	}
+
+	// An invalid recipe is reported
+	if err := os.WriteFile(filepath.Join(ProjectRecipesDir, "broken.yaml"), []byte("name: broken\n"), 0644); err != nil {
+		t.Fatalf("Unable to write broken.yaml: %v", err)
+	}
+	if _, err := LoadRecipes(); err == nil || !strings.Contains(err.Error(), "image is required") {
+		t.Errorf("LoadRecipes should report the recipe without image, got %v", err)
+	}
+}
+
+func TestProjectRecipesDir(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Unable to get current working directory: %v", err)
+	}
+	defer os.Chdir(oldWd)
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Unable to change directory: %v", err)
+	}
+	t.Setenv("HOME", tempDir)
+	defer SetProjectRecipesDir(filepath.Dir(ProjectRecipesDir))
+
+	// The project recipes follow the output directory
+	SetProjectRecipesDir(filepath.Join("build", "dev"))
+	if want := filepath.Join("build", "dev", "recipes"); RecipeSearchPath()[0] != want {
+		t.Errorf("RecipeSearchPath() = %v, want %s first", RecipeSearchPath(), want)
+	}
+
+	// The catalog is loaded once while the recipe files are unchanged
+	first, err := LoadRecipes()
+	if err != nil {
+		t.Fatalf("LoadRecipes returned an error: %v", err)
+	}
+	if second, _ := LoadRecipes(); second != first {
+		t.Error("The catalog should be reused while the recipes are unchanged")
+	}
+
+	// An ejected recipe is previewed without being written
+	mem := NewMemoryFileSystem()
+	previous := SetFileSystem(mem)
+	path, err := EjectRecipe("redis", false)
+	SetFileSystem(previous)
+	if err != nil {
+		t.Fatalf("EjectRecipe returned an error: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("%s should not be written during a preview", path)
+	}
+	if _, ok := mem.Content(path); !ok {
+		t.Errorf("%s should be in the preview", path)
+	}
+
+	// Once written, the project recipe is loaded in a new catalog
+	if _, err := EjectRecipe("redis", false); err != nil {
+		t.Fatalf("EjectRecipe returned an error: %v", err)
+	}
+	reloaded, err := LoadRecipes()
+	if err != nil {
+		t.Fatalf("LoadRecipes returned an error: %v", err)
+	}
+	if recipe, _ := reloaded.Lookup("redis"); reloaded == first || recipe.Source != path {
+		t.Errorf("The ejected recipe should be loaded from %s", path)
+	}
+}
+
+func TestRecipeInitAndMounts(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Unable to get current working directory: %v", err)
+	}
+	defer os.Chdir(oldWd)
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Unable to change directory: %v", err)
+	}
+	t.Setenv("HOME", tempDir)
+
+	serviceList := ServiceList{
+		OutputDir: ".turbotilt",
+		Services: []Options{{
+			ServiceName: "api",
+			Framework:   "quarkus",
+			Port:        "8080",
+			Path:        "api",
+			OutputDir:   ".turbotilt",
+			Services: []scan.ServiceConfig{
+				{Type: scan.MinIO, Resources: []string{"invoices", "archives"}},
+				{Type: scan.Keycloak, Volumes: []string{"keycloak/shop-realm.json:/opt/keycloak/data/import/shop-realm.json:ro"}, Credentials: map[string]string{"KEYCLOAK_REALM": "shop"}},
+				{Type: scan.Mailpit},
+			},
+		}},
+	}
+	if err := GenerateMultiServiceCompose(serviceList); err != nil {
+		t.Fatalf("GenerateMultiServiceCompose returned an error: %v", err)
+	}
+
+	compose, err := os.ReadFile(filepath.Join(".turbotilt", "docker-compose.yml"))
+	if err != nil {
+		t.Fatalf("Unable to read docker-compose.yml: %v", err)
+	}
+	for _, expected := range []string{
+		"  minio-init:\n    image: minio/mc:latest\n    entrypoint: [\"/bin/sh\", \"-c\"]\n",
+		"command: [\"mc alias set local http://minio:9000 minio minio123 && mc mb --ignore-existing local/invoices && mc mb --ignore-existing local/archives\"]",
+		"      minio-init:\n        condition: service_completed_successfully\n",
+		"- '9001:9001'",
+		"- QUARKUS_S3_ENDPOINT_OVERRIDE=http://minio:9000",
+		"- AWS_ENDPOINT_URL_S3=http://minio:9000",
+		"- '8180:8080'",
+		"- ../keycloak/shop-realm.json:/opt/keycloak/data/import/shop-realm.json:ro",
+		"- QUARKUS_OIDC_AUTH_SERVER_URL=http://keycloak:8080/realms/shop",
+		"- QUARKUS_MAILER_HOST=mailpit",
+		"- '8025:8025'",
+	} {
+		if !strings.Contains(string(compose), expected) {
+			t.Errorf("docker-compose.yml should contain %q:\n%s", expected, compose)
+		}
+	}
+}
+
+func TestDatabaseRecipes(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	tests := []struct {
+		service  scan.ServiceConfig
+		image    string
+		expected map[string]string
+	}{
+		{scan.ServiceConfig{Type: scan.MariaDB}, "mariadb:11", map[string]string{"SPRING_DATASOURCE_URL": "jdbc:mariadb://mariadb:3306/app"}},
+		{scan.ServiceConfig{Type: scan.SQLServer}, "mcr.microsoft.com/mssql/server:2022-latest", map[string]string{"SPRING_DATASOURCE_USERNAME": "sa", "SPRING_DATASOURCE_PASSWORD": "Turbotilt_2024"}},
+		{scan.ServiceConfig{Type: scan.OracleXE}, "gvenzl/oracle-xe:21-slim", map[string]string{"SPRING_DATASOURCE_URL": "jdbc:oracle:thin:@//oracle-xe:1521/XEPDB1"}},
+		{scan.ServiceConfig{Type: scan.Cassandra}, "cassandra:5", map[string]string{"SPRING_CASSANDRA_CONTACT_POINTS": "cassandra:9042"}},
+		{scan.ServiceConfig{Type: scan.Neo4j, Credentials: map[string]string{"NEO4J_AUTH": "neo4j/secret"}}, "neo4j:5", map[string]string{"SPRING_NEO4J_AUTHENTICATION_PASSWORD": "secret"}},
+		{scan.ServiceConfig{Type: scan.Neo4j, Credentials: map[string]string{"NEO4J_AUTH": "none"}}, "neo4j:5", map[string]string{"SPRING_NEO4J_AUTHENTICATION_USERNAME": "", "SPRING_NEO4J_AUTHENTICATION_PASSWORD": ""}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.service.Type), func(t *testing.T) {
+			definitions, _ := dependencyServiceDefinitions(tt.service, "")
+			if len(definitions) == 0 || definitions[0].Image != tt.image {
+				t.Fatalf("Unexpected definitions %+v, want image %s", definitions, tt.image)
+			}
+			env := connectionEnvironment(Options{Framework: "spring", Services: []scan.ServiceConfig{tt.service}})
+			for key, value := range tt.expected {
+				if env[key] != value {
+					t.Errorf("%s = %q, want %q", key, env[key], value)
+				}
+			}
+		})
+	}
+}
+
+func TestLocalStackRecipe(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	service := scan.ServiceConfig{
+		Type:        scan.LocalStack,
+		Credentials: map[string]string{"SERVICES": "s3,sqs"},
+		Resources:   []string{"invoices", "queue:orders", "table:customers"},
+	}
+	definitions, _ := dependencyServiceDefinitions(service, "")
+	if len(definitions) != 2 || definitions[1].Name != "localstack-init" || !definitions[1].OneShot {
+		t.Fatalf("Expected the localstack service and its init service, got %+v", definitions)
+	}
+	if definitions[0].Environment["SERVICES"] != "s3,sqs" {
+		t.Errorf("SERVICES = %q, want s3,sqs", definitions[0].Environment["SERVICES"])
+	}
+	for _, expected := range []string{
+		"AWS_ENDPOINT_URL=http://localstack:4566",
+		"aws s3 mb s3://invoices",
+		"aws sqs create-queue --queue-name orders",
+		"aws dynamodb create-table --table-name customers",
+	} {
+		if !strings.Contains(definitions[1].Script, expected) {
+			t.Errorf("Init script should contain %q: %s", expected, definitions[1].Script)
+		}
+	}
+	if strings.Contains(definitions[1].Script, "create-topic") {
+		t.Errorf("Init script should not create topics: %s", definitions[1].Script)
+	}
+
+	env := connectionEnvironment(Options{Framework: "spring", Services: []scan.ServiceConfig{service}})
+	for key, value := range map[string]string{
+		"AWS_ENDPOINT_URL":               "http://localstack:4566",
+		"AWS_REGION":                     "us-east-1",
+		"SPRING_CLOUD_AWS_ENDPOINT":      "http://localstack:4566",
+		"SPRING_CLOUD_AWS_REGION_STATIC": "us-east-1",
+	} {
+		if env[key] != value {
+			t.Errorf("%s = %q, want %q", key, env[key], value)
+		}
+	}
+}
+
+func TestKafkaRecipe(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	service := scan.ServiceConfig{Type: scan.Kafka, Port: "9094", Resources: []string{"topic:orders", "queue:ignored"}}
+	definitions, _ := dependencyServiceDefinitions(service, "")
+	if len(definitions) != 2 || definitions[1].Name != "kafka-init" {
+		t.Fatalf("Expected the kafka service and its init service, got %+v", definitions)
+	}
+	if definitions[0].Port != "9094:9092" {
+		t.Errorf("Port = %q, want 9094:9092", definitions[0].Port)
+	}
+	if want := "INTERNAL://kafka:19092,EXTERNAL://localhost:9094"; definitions[0].Environment["KAFKA_ADVERTISED_LISTENERS"] != want {
+		t.Errorf("KAFKA_ADVERTISED_LISTENERS = %q, want %q", definitions[0].Environment["KAFKA_ADVERTISED_LISTENERS"], want)
+	}
+	if want := "/opt/kafka/bin/kafka-topics.sh --bootstrap-server kafka:19092 --create --if-not-exists --topic orders --partitions 1 --replication-factor 1"; definitions[1].Script != want {
+		t.Errorf("Init script = %q, want %q", definitions[1].Script, want)
+	}
+
+	env := connectionEnvironment(Options{Framework: "quarkus", Services: []scan.ServiceConfig{service}})
+	if env["KAFKA_BOOTSTRAP_SERVERS"] != "kafka:19092" {
+		t.Errorf("KAFKA_BOOTSTRAP_SERVERS = %q, want kafka:19092", env["KAFKA_BOOTSTRAP_SERVERS"])
+	}
+}
+
+func TestSeedFilesAndQueues(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Unable to get current working directory: %v", err)
+	}
+	defer os.Chdir(oldWd)
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Unable to change directory: %v", err)
+	}
+	t.Setenv("HOME", tempDir)
+
+	serviceList := ServiceList{
+		OutputDir: ".turbotilt",
+		Services: []Options{{
+			ServiceName: "api",
+			Framework:   "spring",
+			Port:        "8080",
+			Path:        "api",
+			OutputDir:   ".turbotilt",
+			Services: []scan.ServiceConfig{
+				{Type: scan.MongoDB, Seeds: []string{"mongo/01-users.js"}},
+				{Type: scan.Redis, Seeds: []string{"redis/cache.redis"}},
+				{Type: scan.RabbitMQ, Resources: []string{"queue:orders"}},
+			},
+		}},
+	}
+	if err := GenerateMultiServiceCompose(serviceList); err != nil {
+		t.Fatalf("GenerateMultiServiceCompose returned an error: %v", err)
+	}
+
+	compose, err := os.ReadFile(filepath.Join(".turbotilt", "docker-compose.yml"))
+	if err != nil {
+		t.Fatalf("Unable to read docker-compose.yml: %v", err)
+	}
+	for _, expected := range []string{
+		"- ../mongo/01-users.js:/docker-entrypoint-initdb.d/01-users.js:ro",
+		"  redis-init:\n    image: redis:latest\n",
+		"command: [\"redis-cli -h redis -p 6379 < /seed/cache.redis\"]\n    volumes:\n      - ../redis/cache.redis:/seed/cache.redis:ro\n",
+		"  rabbitmq-init:\n",
+		"for i in $$(seq 30); do rabbitmqadmin -H rabbitmq -u guest -p guest list vhosts",
+		"rabbitmqadmin -H rabbitmq -u guest -p guest declare queue name=orders durable=true",
+		"      redis-init:\n        condition: service_completed_successfully\n",
+	} {
+		if !strings.Contains(string(compose), expected) {
+			t.Errorf("docker-compose.yml should contain %q:\n%s", expected, compose)
+		}
+	}
+	if strings.Contains(string(compose), "mongodb-init") {
+		t.Errorf("MongoDB runs its seed scripts itself:\n%s", compose)
+	}
+}
+
+func TestRecipeProjectMounts(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Unable to get current working directory: %v", err)
+	}
+	defer os.Chdir(oldWd)
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Unable to change directory: %v", err)
+	}
+	t.Setenv("HOME", tempDir)
+
+	files := map[string]string{
+		"auth/keycloak/shop-realm.json":    `{"realm": "shop", "enabled": true}`,
+		"auth/keycloak/users.json":         `[{"username": "alice"}]`,
+		"broker/rabbitmq/definitions.json": `{"queues": []}`,
+		"app/pom.xml":                      "<artifactId>spring-cloud-starter-config</artifactId>",
+		"app/config/messages.txt":          "not a configuration file",
+		"app/config-server/orders.yml":     "orders:\n  page-size: 20\n",
+		"repo/orders.yml":                  "orders:\n  page-size: 20\n",
+	}
+	for name, content := range files {
+		path := filepath.FromSlash(name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Unable to create %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Unable to write %s: %v", name, err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		service scan.ServiceConfig
+		volumes []string
+		env     map[string]string
+		script  string // Command of the init service, whose volumes are checked when set
+	}{
+		{
+			name:    "Keycloak realm exports",
+			service: scan.ServiceConfig{Type: scan.Keycloak, Dir: "auth"},
+			volumes: []string{"../auth/keycloak/shop-realm.json:/opt/keycloak/data/import/shop-realm.json:ro"},
+			env:     map[string]string{"KEYCLOAK_REALM": "shop"},
+		},
+		{
+			name:    "RabbitMQ definitions",
+			service: scan.ServiceConfig{Type: scan.RabbitMQ, Dir: "broker"},
+			volumes: []string{"../broker/rabbitmq/definitions.json:/definitions.json:ro"},
+			script:  "-u guest -p guest import /definitions.json",
+		},
+		{
+			name:    "Definitions set in the manifest",
+			service: scan.ServiceConfig{Type: scan.RabbitMQ, Mounts: map[string]string{"definitions": "auth/keycloak/users.json"}},
+			volumes: []string{"../auth/keycloak/users.json:/definitions.json:ro"},
+			script:  "import /definitions.json",
+		},
+		{
+			// The application is not its config repository, its subdirectory holding configuration files is
+			name:    "Config repository of an application",
+			service: scan.ServiceConfig{Type: scan.ConfigServer, Dir: "app"},
+			volumes: []string{"../app/config-server:/config:ro"},
+		},
+		{
+			name:    "Config repository",
+			service: scan.ServiceConfig{Type: scan.ConfigServer, Dir: "repo"},
+			volumes: []string{"../repo:/config:ro"},
+		},
+		{
+			name:    "Volumes of the service",
+			service: scan.ServiceConfig{Type: scan.ConfigServer, Dir: "repo", Volumes: []string{"./shared:/config:ro"}},
+			volumes: []string{"../shared:/config:ro"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			definitions, _ := dependencyServiceDefinitions(tt.service, ".turbotilt")
+			if len(definitions) == 0 {
+				t.Fatalf("No definition for %s", tt.service.Type)
+			}
+			definition := definitions[0]
+			if tt.script != "" {
+				if len(definitions) < 2 || !strings.Contains(definitions[1].Script, tt.script) {
+					t.Fatalf("Definitions %+v, want an init service running %q", definitions, tt.script)
+				}
+				definition = definitions[1]
+			}
+			var volumes []string
+			for _, volume := range definition.Volumes {
+				if strings.HasPrefix(volume, ".") {
+					volumes = append(volumes, volume)
+				}
+			}
+			if strings.Join(volumes, ",") != strings.Join(tt.volumes, ",") {
+				t.Errorf("Volumes = %v, want %v", volumes, tt.volumes)
+			}
+			for key, value := range tt.env {
+				if got := definition.Environment[key]; got != value {
+					t.Errorf("%s = %q, want %q", key, got, value)
+				}
+			}
+		})
+	}
+}
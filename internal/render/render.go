@@ -1,9 +1,9 @@
 package render
 
 import (
+	"bytes"
 	"fmt"
 	"io"
-	"os"
 	"text/template"
 	"turbotilt/internal/scan"
 )
@@ -19,11 +19,28 @@ type Options struct {
 	Path        string               // Service path (for multi-service projects)
 	Services    []scan.ServiceConfig // Detected dependent services
 	EnvFile     string               // Path to environment file
+	WatchPaths  []string             // Additional paths synced by docker compose watch, relative to the service
+	Debug       bool                 // Start the JVM with the JDWP agent for remote debugging
+	DebugPort   string               // Host port of the JDWP agent (assigned when empty)
+
+	BuildStrategy scan.ImageBuildStrategy // How the image is built (detected when empty)
+	BuildSystem   string                  // Build system (maven, gradle; detected when empty)
+	Force         bool                    // Overwrite files not generated by Turbotilt or edited since
+	OutputDir     string                  // Directory receiving the generated files (current directory when empty)
 }
 
 // ServiceList contains the list of services for multi-service file generation
 type ServiceList struct {
-	Services []Options // List of application services
+	Services      []Options            // List of application services
+	Force         bool                 // Overwrite files not generated by Turbotilt or edited since
+	OutputDir     string               // Directory receiving the generated files (current directory when empty)
+	Observability ObservabilityOptions // Observability stack added to the environment
+	Gateway       GatewayOptions       // Gateway routing <service>.localhost to the application services
+	TLS           TLSOptions           // HTTPS with certificates of the development CA
+	Tools         []string             // Administration tools connected to the dependent services (adminer, pgadmin, etc.)
+	Seed          SeedOptions          // Migrations and seed scripts run before the application services
+	Chaos         ChaosOptions         // Toxiproxy between the application services and dependent services
+	Stubs         StubOptions          // WireMock services replacing the third-party HTTP APIs
 }
 
 // DockerfileRenderer defines an interface for Dockerfile generation
@@ -35,53 +52,58 @@ type DockerfileRenderer interface {
 	RenderGenericDockerfile(w io.Writer, opts Options) error
 }
 
-// GenerateDockerfile generates a Dockerfile adapted to the detected framework
+// GenerateDockerfile generates a Dockerfile adapted to the detected framework in the service directory,
+// or in the output directory when one is set.
+// Nothing is written when the project provides its own Dockerfile or builds its image with Jib or Buildpacks.
 func GenerateDockerfile(opts Options) error {
-	f, err := os.Create("Dockerfile")
-	if err != nil {
-		return fmt.Errorf("error creating Dockerfile: %w", err)
+	if !UsesGeneratedDockerfile(opts) {
+		return nil
 	}
-	defer f.Close()
 
+	var buf bytes.Buffer
+	var err error
 	switch opts.Framework {
 	case FrameworkSpring:
-		return defaultRenderer.RenderSpringDockerfile(f, opts)
+		err = defaultRenderer.RenderSpringDockerfile(&buf, opts)
 	case FrameworkQuarkus:
-		return defaultRenderer.RenderQuarkusDockerfile(f, opts)
+		err = defaultRenderer.RenderQuarkusDockerfile(&buf, opts)
 	case FrameworkMicronaut:
-		return defaultRenderer.RenderMicronautDockerfile(f, opts)
+		err = defaultRenderer.RenderMicronautDockerfile(&buf, opts)
 	case FrameworkJava:
-		return defaultRenderer.RenderJavaDockerfile(f, opts)
+		err = defaultRenderer.RenderJavaDockerfile(&buf, opts)
 	default:
-		return defaultRenderer.RenderGenericDockerfile(f, opts)
+		err = defaultRenderer.RenderGenericDockerfile(&buf, opts)
+	}
+	if err != nil {
+		return fmt.Errorf("error rendering Dockerfile: %w", err)
+	}
+
+	if err := ensureOutputDir(opts.OutputDir); err != nil {
+		return fmt.Errorf("error creating output directory: %w", err)
 	}
+	return writeGeneratedFile(DockerfilePath(opts), buf.Bytes(), opts.Force)
 }
 
 // GenerateCompose generates a docker-compose.yml file
 func GenerateCompose(opts Options) error {
-	f, err := os.Create("docker-compose.yml")
-	if err != nil {
-		return fmt.Errorf("error creating docker-compose.yml: %w", err)
-	}
-	defer f.Close()
-
-	// Check if an environment file exists
-	envFile := getEnvFilePath(".")
+	data := newComposeTemplateData(opts)
 
 	// Build the template with or without an environment file
-	var tmplStr string
-	if envFile != "" {
-		// Add the environment file path to the options
-		opts.EnvFile = envFile
+	tmplStr := ComposeTemplate
+	if data.EnvFile != "" {
 		tmplStr = ComposeTemplateWithEnvFile
-	} else {
-		tmplStr = ComposeTemplate
 	}
 
-	t, err := template.New("compose").Parse(tmplStr)
+	ts := NewTemplateService()
+	t, err := template.New("compose").Funcs(ts.FuncMap).Parse(tmplStr)
 	if err != nil {
 		return err
 	}
 
-	return t.Execute(f, opts)
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return err
+	}
+
+	return writeOutputFile(opts.OutputDir, "docker-compose.yml", buf.Bytes(), opts.Force)
 }
@@ -0,0 +1,180 @@
+package render
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"turbotilt/internal/scan"
+)
+
+const (
+	// migrateServiceName is the compose service running the migrations
+	migrateServiceName = "migrate"
+
+	// seedServiceName is the compose service running the seed scripts, or the Tilt resource running the seed command
+	seedServiceName = "seed"
+
+	// seedScriptsDir is where the seed scripts are mounted in the seed service
+	seedScriptsDir = "/seed"
+)
+
+// migrationImages are the images running the migrations, by tool
+var migrationImages = map[string]string{
+	scan.MigrationFlyway:    "flyway/flyway:10",
+	scan.MigrationLiquibase: "liquibase/liquibase:4.29",
+}
+
+// SeedOptions configures the migrations and the SQL seed scripts run against a database before the
+// application services start, by one-shot compose services or by a command of a Tilt local_resource
+type SeedOptions struct {
+	Enabled    bool
+	Database   string   // Type of the dependent service seeded (the first JDBC database when empty)
+	Tool       string   // Migration tool (flyway, liquibase), empty without migrations
+	Migrations string   // Migration directory, relative to the current directory
+	Changelog  string   // Liquibase master changelog, relative to the migration directory
+	Scripts    []string // SQL scripts run after the migrations, relative to the current directory
+	Command    string   // Command run on the host by a Tilt local_resource, replacing the compose services
+}
+
+// IsValidMigrationTool checks if a migration tool is supported
+func IsValidMigrationTool(tool string) bool {
+	_, ok := migrationImages[strings.ToLower(tool)]
+	return ok
+}
+
+// seedDatabase returns the dependent service seeded and its recipe: the service of the type of the
+// seed database, or else the first service the applications connect to with JDBC
+func seedDatabase(serviceList ServiceList) (scan.ServiceConfig, *Recipe, bool) {
+	catalog := defaultCatalog()
+	var wanted *Recipe
+	if serviceList.Seed.Database != "" {
+		recipe, ok := catalog.Lookup(serviceList.Seed.Database)
+		if !ok {
+			return scan.ServiceConfig{}, nil, false
+		}
+		wanted = recipe
+	}
+
+	for _, opts := range serviceList.Services {
+		for _, service := range opts.Services {
+			recipe, ok := catalog.Lookup(string(service.Type))
+			if !ok {
+				continue
+			}
+			if wanted != nil && recipe.Name == wanted.Name {
+				return service, recipe, true
+			}
+			if wanted == nil && recipe.Connection["spring"]["SPRING_DATASOURCE_URL"] != "" {
+				return service, recipe, true
+			}
+		}
+	}
+	return scan.ServiceConfig{}, nil, false
+}
+
+// seedDefinitions returns the one-shot services running the migrations and the seed scripts,
+// which the application services wait for
+func seedDefinitions(serviceList ServiceList) ([]ComposeServiceDefinition, error) {
+	seed := serviceList.Seed
+	if !seed.Enabled || seed.Command != "" {
+		return nil, nil
+	}
+
+	service, recipe, ok := seedDatabase(serviceList)
+	if !ok {
+		return nil, fmt.Errorf("no database to seed: declare a dependent service with a JDBC connection (postgres, mysql...)")
+	}
+	database := recipe.definition(service)
+
+	var definitions []ComposeServiceDefinition
+	if seed.Tool != "" {
+		migrate, err := migrateDefinition(seed, recipe, database, serviceList.OutputDir)
+		if err != nil {
+			return nil, err
+		}
+		definitions = append(definitions, migrate)
+	}
+
+	if len(seed.Scripts) > 0 {
+		if recipe.Client == "" {
+			return nil, fmt.Errorf("seed scripts are not supported for %s (its recipe has no client)", recipe.Name)
+		}
+		client := recipe.expand(recipe.Client, database, service)
+		definition := ComposeServiceDefinition{
+			Name:      seedServiceName,
+			Image:     database.Image,
+			DependsOn: []string{database.Name},
+			OneShot:   true,
+		}
+		var commands []string
+		for _, script := range seed.Scripts {
+			target := path.Join(seedScriptsDir, filepath.Base(script))
+			commands = append(commands, client+" < "+target)
+			definition.Volumes = append(definition.Volumes, mountFromOutput(serviceList.OutputDir, script+":"+target+":ro"))
+		}
+		definition.Script = strings.Join(commands, " && ")
+		if seed.Tool != "" {
+			definition.Completed = []string{migrateServiceName}
+		}
+		definitions = append(definitions, definition)
+	}
+	return definitions, nil
+}
+
+// migrateDefinition returns the one-shot service running the migrations of the seed with its tool,
+// connected to the database with the JDBC settings of its recipe
+func migrateDefinition(seed SeedOptions, recipe *Recipe, database ComposeServiceDefinition, outputDir string) (ComposeServiceDefinition, error) {
+	tool := strings.ToLower(seed.Tool)
+	image, ok := migrationImages[tool]
+	if !ok {
+		return ComposeServiceDefinition{}, fmt.Errorf("migration tool '%s' not supported (flyway, liquibase)", seed.Tool)
+	}
+	if seed.Migrations == "" {
+		return ComposeServiceDefinition{}, fmt.Errorf("no migration directory for %s", tool)
+	}
+
+	jdbc := recipe.connectionEnv("spring", database)
+	url := jdbc["SPRING_DATASOURCE_URL"]
+	if url == "" {
+		return ComposeServiceDefinition{}, fmt.Errorf("migrations are not supported for %s (its recipe has no JDBC connection)", recipe.Name)
+	}
+
+	definition := ComposeServiceDefinition{
+		Name:      migrateServiceName,
+		Image:     image,
+		DependsOn: []string{database.Name},
+		OneShot:   true,
+	}
+	switch tool {
+	case scan.MigrationFlyway:
+		definition.Command = "migrate"
+		definition.Environment = map[string]string{
+			"FLYWAY_URL":             url,
+			"FLYWAY_USER":            jdbc["SPRING_DATASOURCE_USERNAME"],
+			"FLYWAY_PASSWORD":        jdbc["SPRING_DATASOURCE_PASSWORD"],
+			"FLYWAY_LOCATIONS":       "filesystem:/flyway/sql",
+			"FLYWAY_CONNECT_RETRIES": "30",
+		}
+		definition.Volumes = []string{mountFromOutput(outputDir, seed.Migrations+":/flyway/sql:ro")}
+
+	case scan.MigrationLiquibase:
+		changelog := seed.Changelog
+		if changelog == "" {
+			changelog = scan.LiquibaseChangelog(seed.Migrations)
+		}
+		definition.Command = fmt.Sprintf("--changelog-file=%s --search-path=/liquibase/changelog update", changelog)
+		definition.Environment = map[string]string{
+			"LIQUIBASE_COMMAND_URL":      url,
+			"LIQUIBASE_COMMAND_USERNAME": jdbc["SPRING_DATASOURCE_USERNAME"],
+			"LIQUIBASE_COMMAND_PASSWORD": jdbc["SPRING_DATASOURCE_PASSWORD"],
+		}
+		// The MySQL driver is not shipped with the image for licensing reasons
+		if strings.HasPrefix(url, "jdbc:mysql:") {
+			definition.Environment["INSTALL_MYSQL"] = "true"
+		}
+		definition.Volumes = []string{mountFromOutput(outputDir, seed.Migrations+":/liquibase/changelog:ro")}
+	}
+	return definition, nil
+}
@@ -0,0 +1,105 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"turbotilt/internal/scan"
+)
+
+func TestSeed(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Unable to get current working directory: %v", err)
+	}
+	defer os.Chdir(oldWd)
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Unable to change directory: %v", err)
+	}
+	t.Setenv("HOME", tempDir)
+
+	newServiceList := func(seed SeedOptions) ServiceList {
+		return ServiceList{
+			OutputDir: ".turbotilt",
+			Force:     true,
+			Seed:      seed,
+			Services: []Options{{
+				ServiceName: "api",
+				Framework:   "spring",
+				Port:        "8080",
+				Path:        "api",
+				OutputDir:   ".turbotilt",
+				Services: []scan.ServiceConfig{
+					{Type: scan.Redis},
+					{Type: scan.PostgreSQL, Credentials: map[string]string{"POSTGRES_USER": "shop"}},
+				},
+			}},
+		}
+	}
+
+	// Migrations and seed scripts run by one-shot compose services
+	serviceList := newServiceList(SeedOptions{
+		Enabled:    true,
+		Tool:       scan.MigrationFlyway,
+		Migrations: "api/src/main/resources/db/migration",
+		Scripts:    []string{"seed/products.sql"},
+	})
+	if err := GenerateMultiServiceCompose(serviceList); err != nil {
+		t.Fatalf("GenerateMultiServiceCompose returned an error: %v", err)
+	}
+	compose, err := os.ReadFile(filepath.Join(".turbotilt", "docker-compose.yml"))
+	if err != nil {
+		t.Fatalf("Unable to read docker-compose.yml: %v", err)
+	}
+	for _, expected := range []string{
+		"  migrate:\n    image: flyway/flyway:10\n    command: migrate\n",
+		"- FLYWAY_URL=jdbc:postgresql://postgres:5432/app",
+		"- FLYWAY_USER=shop",
+		"- ../api/src/main/resources/db/migration:/flyway/sql:ro",
+		"  seed:\n    image: postgres:latest\n",
+		`command: ["PGPASSWORD=postgres psql -v ON_ERROR_STOP=1 -h postgres -U shop -d app < /seed/products.sql"]`,
+		"- ../seed/products.sql:/seed/products.sql:ro",
+		"      migrate:\n        condition: service_completed_successfully\n      seed:\n        condition: service_completed_successfully\n",
+	} {
+		if !strings.Contains(string(compose), expected) {
+			t.Errorf("docker-compose.yml should contain %q:\n%s", expected, compose)
+		}
+	}
+
+	// Scripts need a database whose recipe has a client
+	serviceList.Seed = SeedOptions{Enabled: true, Database: "redis", Scripts: []string{"seed/products.sql"}}
+	if err := GenerateMultiServiceCompose(serviceList); err == nil || !strings.Contains(err.Error(), "no client") {
+		t.Errorf("GenerateMultiServiceCompose should reject the scripts for Redis, got %v", err)
+	}
+
+	// A seed command run by a Tilt local_resource once the database is up
+	serviceList = newServiceList(SeedOptions{Enabled: true, Command: "./mvnw -pl api flyway:migrate"})
+	if err := GenerateMultiServiceCompose(serviceList); err != nil {
+		t.Fatalf("GenerateMultiServiceCompose returned an error: %v", err)
+	}
+	compose, err = os.ReadFile(filepath.Join(".turbotilt", "docker-compose.yml"))
+	if err != nil {
+		t.Fatalf("Unable to read docker-compose.yml: %v", err)
+	}
+	if strings.Contains(string(compose), "migrate:") {
+		t.Errorf("The seed command should replace the compose services:\n%s", compose)
+	}
+	if err := GenerateMultiServiceTiltfile(serviceList); err != nil {
+		t.Fatalf("GenerateMultiServiceTiltfile returned an error: %v", err)
+	}
+	tiltfile, err := os.ReadFile(filepath.Join(".turbotilt", "Tiltfile"))
+	if err != nil {
+		t.Fatalf("Unable to read Tiltfile: %v", err)
+	}
+	for _, expected := range []string{
+		"local_resource(\n  'seed',\n  \"./mvnw -pl api flyway:migrate\",\n  dir='..',\n  resource_deps=['postgres'],\n",
+		"dc_resource('api', labels=['app'], resource_deps=['seed'])",
+	} {
+		if !strings.Contains(string(tiltfile), expected) {
+			t.Errorf("Tiltfile should contain %q:\n%s", expected, tiltfile)
+		}
+	}
+}
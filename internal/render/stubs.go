@@ -0,0 +1,134 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"turbotilt/internal/scan"
+)
+
+const (
+	// DefaultStubsDir is the project directory of the WireMock stubs used when none is set
+	DefaultStubsDir = "stubs"
+
+	// stubRoot is the root directory of WireMock in its container, holding mappings/ and __files/
+	stubRoot = "/home/wiremock"
+)
+
+// StubOptions replaces the third-party HTTP APIs called by the application services with
+// WireMock services, one per external host, serving the stubs of the project
+type StubOptions struct {
+	Enabled bool
+	Dir     string   // Directory of the stubs, stubs/<host> for a host or shared (DefaultStubsDir when empty)
+	Hosts   []string // External hosts replaced (all the detected ones when empty)
+}
+
+// stubNamePattern matches the characters of a host not allowed in a compose service name
+var stubNamePattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// stubServiceName returns the compose service of WireMock replacing an external host
+func stubServiceName(host string) string {
+	return "wiremock-" + strings.Trim(stubNamePattern.ReplaceAllString(strings.ToLower(host), "-"), "-")
+}
+
+// stubbedURLs returns the external URLs of an application service replaced by WireMock
+func stubbedURLs(opts Options, options StubOptions) []scan.ExternalURL {
+	if !options.Enabled {
+		return nil
+	}
+
+	wanted := make(map[string]bool)
+	for _, host := range options.Hosts {
+		wanted[strings.ToLower(host)] = true
+	}
+	var urls []scan.ExternalURL
+	for _, url := range scan.DetectExternalURLs(servicePathOf(opts)) {
+		if len(wanted) == 0 || wanted[url.Host] {
+			urls = append(urls, url)
+		}
+	}
+	return urls
+}
+
+// stubURLs points the external URLs of an application service to their WireMock service,
+// keeping their path, unless its environment file sets them
+func stubURLs(service *ComposeServiceDefinition, opts Options, urls []scan.ExternalURL) {
+	envFileKeys := readEnvFileKeys(getEnvFilePath(servicePathOf(opts)))
+	stubs := make(map[string]bool)
+	for _, url := range urls {
+		_, rest, _ := strings.Cut(url.URL, "://")
+		path := ""
+		if i := strings.IndexAny(rest, "/?"); i >= 0 {
+			path = rest[i:]
+		}
+
+		key := propertyEnvName(opts.Framework, url.Property)
+		if !envFileKeys[key] {
+			service.Environment[key] = "http://" + stubServiceName(url.Host) + ":8080" + path
+		}
+		stubs[stubServiceName(url.Host)] = true
+	}
+	service.DependsOn = append(service.DependsOn, sortedKeys(stubs)...)
+}
+
+// stubServiceDefinitions returns the WireMock services of the external hosts of the URLs. The stubs
+// of a host are mounted from <dir>/<host>, or else from <dir> shared by all the hosts.
+func stubServiceDefinitions(urls []scan.ExternalURL, options StubOptions, outputDir string) []ComposeServiceDefinition {
+	hosts := make(map[string]bool)
+	for _, url := range urls {
+		hosts[url.Host] = true
+	}
+
+	dir := getOrDefault(options.Dir, DefaultStubsDir)
+	var definitions []ComposeServiceDefinition
+	for _, host := range sortedKeys(hosts) {
+		definition := ComposeServiceDefinition{
+			Name:    stubServiceName(host),
+			Image:   "wiremock/wiremock:3.9.1",
+			Command: "--global-response-templating --disable-banner",
+		}
+		for _, source := range []string{filepath.Join(dir, host), dir} {
+			if stat, err := os.Stat(source); err == nil && stat.IsDir() {
+				// A bare directory name would be a named volume
+				if !strings.ContainsAny(source, `./\`) {
+					source = "./" + source
+				}
+				definition.Volumes = []string{mountFromOutput(outputDir, source+":"+stubRoot)}
+				break
+			}
+		}
+		definitions = append(definitions, definition)
+	}
+	return definitions
+}
+
+// propertyEnvName returns the environment variable overriding a property of the framework:
+// Spring Boot removes the dashes (PAYMENTS_BASEURL), the others replace them (PAYMENTS_BASE_URL)
+func propertyEnvName(framework, property string) string {
+	name := strings.ToUpper(property)
+	if framework == FrameworkSpring {
+		name = strings.ReplaceAll(name, "-", "")
+	}
+	var sb strings.Builder
+	for _, r := range name {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteRune('_')
+		}
+	}
+	return sb.String()
+}
+
+// stubServiceNames returns the WireMock services replacing the external hosts of the application services
+func stubServiceNames(serviceList ServiceList) []string {
+	names := make(map[string]bool)
+	for _, opts := range serviceList.Services {
+		for _, url := range stubbedURLs(opts, serviceList.Stubs) {
+			names[stubServiceName(url.Host)] = true
+		}
+	}
+	return sortedKeys(names)
+}
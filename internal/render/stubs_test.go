@@ -0,0 +1,76 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStubs(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Unable to get current working directory: %v", err)
+	}
+	defer os.Chdir(oldWd)
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Unable to change directory: %v", err)
+	}
+	t.Setenv("HOME", tempDir)
+
+	// Stubs of Stripe in their own directory, the other hosts share the stubs directory
+	files := map[string]string{
+		"api/src/main/resources/application.properties": "payments.base-url=https://api.stripe.com/v1\nshipping.base-url=https://api.ups.com\n",
+		"stubs/api.stripe.com/mappings/charges.json":    `{"request": {"url": "/v1/charges"}, "response": {"status": 200}}`,
+		"stubs/mappings/rates.json":                     `{"request": {"url": "/rates"}, "response": {"status": 200}}`,
+	}
+	for name, content := range files {
+		os.MkdirAll(filepath.Dir(name), 0755)
+		if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatalf("Unable to write %s: %v", name, err)
+		}
+	}
+
+	serviceList := ServiceList{
+		OutputDir: ".turbotilt",
+		Force:     true,
+		Stubs:     StubOptions{Enabled: true},
+		Services: []Options{{
+			ServiceName: "api",
+			Framework:   "spring",
+			Port:        "8080",
+			Path:        "api",
+			OutputDir:   ".turbotilt",
+		}},
+	}
+	if err := GenerateMultiServiceCompose(serviceList); err != nil {
+		t.Fatalf("GenerateMultiServiceCompose returned an error: %v", err)
+	}
+	compose, err := os.ReadFile(filepath.Join(".turbotilt", "docker-compose.yml"))
+	if err != nil {
+		t.Fatalf("Unable to read docker-compose.yml: %v", err)
+	}
+	for _, expected := range []string{
+		"- PAYMENTS_BASEURL=http://wiremock-api-stripe-com:8080/v1",
+		"- SHIPPING_BASEURL=http://wiremock-api-ups-com:8080",
+		"  wiremock-api-stripe-com:\n    image: wiremock/wiremock:3.9.1\n",
+		"- ../stubs/api.stripe.com:/home/wiremock",
+		"- ../stubs:/home/wiremock",
+	} {
+		if !strings.Contains(string(compose), expected) {
+			t.Errorf("docker-compose.yml should contain %q:\n%s", expected, compose)
+		}
+	}
+
+	// Only the selected hosts are replaced, with the naming of the environment of the framework
+	serviceList.Stubs.Hosts = []string{"api.ups.com"}
+	serviceList.Services[0].Framework = "quarkus"
+	if err := GenerateMultiServiceCompose(serviceList); err != nil {
+		t.Fatalf("GenerateMultiServiceCompose returned an error: %v", err)
+	}
+	compose, _ = os.ReadFile(filepath.Join(".turbotilt", "docker-compose.yml"))
+	if !strings.Contains(string(compose), "- SHIPPING_BASE_URL=http://wiremock-api-ups-com:8080") || strings.Contains(string(compose), "wiremock-api-stripe-com") {
+		t.Errorf("Only api.ups.com should be replaced:\n%s", compose)
+	}
+}
@@ -0,0 +1,278 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"turbotilt/internal/scan"
+)
+
+// The types of this file are the data contract of the templates: fields are only added,
+// never renamed or removed, so that customized templates keep working.
+
+// ServiceTemplateData contains the data of an application service.
+// It is the data of the Dockerfile templates, and the elements of .Services in the multi-service Tiltfile template.
+type ServiceTemplateData struct {
+	Name          string                   // Service name
+	AppName       string                   // Application name
+	Path          string                   // Service directory, relative to the project root
+	Framework     string                   // spring, quarkus, micronaut, java
+	Port          string                   // Exposed port
+	JDKVersion    string                   // JDK version
+	DevMode       bool                     // Development mode
+	BuildSystem   string                   // maven, gradle
+	BuildStrategy string                   // dockerfile, existing-dockerfile, jib, buildpacks
+	BuildCommand  string                   // Command for custom_build when the image is not built from a Dockerfile
+	BuildDeps     []string                 // Paths triggering a custom_build, relative to the output directory
+	Image         string                   // Image built by custom_build and referenced by the compose service
+	Context       string                   // Build context, relative to the output directory
+	Dockerfile    string                   // Dockerfile, relative to the output directory
+	EnvFile       string                   // Environment file, relative to the output directory
+	DebugPort     string                   // Host port of the JDWP agent, empty when debugging is disabled
+	GatewayURL    string                   // URL of the service behind the gateway, empty without gateway
+	Dependencies  []DependencyTemplateData // Dependent services of the service
+}
+
+// DependencyTemplateData contains the data of a dependent service (database, broker...)
+type DependencyTemplateData struct {
+	Name        string            // Compose service name
+	Type        string            // mysql, postgres, mongodb, redis, kafka, rabbitmq, elasticsearch
+	Image       string            // Image reference
+	Version     string            // Image version
+	Port        string            // Port mapping (host:container)
+	Credentials map[string]string // Credentials declared or detected
+}
+
+// TiltfileTemplateData contains the data for the Tiltfile template.
+// The service fields (Framework, Port, BuildCommand...) describe the service of a single-service project.
+type TiltfileTemplateData struct {
+	Framework      string
+	AppName        string
+	Port           string
+	Date           string
+	DevMode        bool
+	Services       []interface{}            // Application services (ServiceTemplateData) of a multi-service project, dependent services as detected (scan.ServiceConfig) of a single-service project
+	Dependencies   []DependencyTemplateData // Dependent services of all the application services
+	IsMultiService bool                     // Indicates if this is a multi-service project
+	BuildStrategy  string                   // Image build strategy (dockerfile, existing-dockerfile, jib, buildpacks)
+	BuildCommand   string                   // Command for custom_build when the image is not built from a Dockerfile
+	BuildDeps      []string                 // Paths triggering a custom_build
+	Image          string                   // Image built by custom_build and referenced by the compose service
+	Context        string                   // Build context, relative to the Tiltfile
+	Dockerfile     string                   // Dockerfile, relative to the Tiltfile
+	DebugPort      string                   // Host port of the JDWP agent, empty when debugging is disabled
+	Observability  bool                     // Observability stack (collector, Jaeger, Prometheus, Grafana) added
+	Gateway        bool                     // Gateway routing <service>.localhost to the application services added
+	SeedCommand    string                   // Command of the local_resource seeding the database, empty when seeded by compose
+	SeedDir        string                   // Directory of the seed command (the project root), relative to the Tiltfile
+	SeedDeps       []string                 // Resources the seed command waits for (the database)
+	ChaosURL       string                   // Address of the Toxiproxy API, empty without chaos
+	Stubs          []string                 // WireMock services replacing third-party APIs
+}
+
+// ComposeTemplateData contains the data for the docker-compose.yml templates
+type ComposeTemplateData struct {
+	ServiceName  string                   // Compose service name of the application
+	Framework    string                   // spring, quarkus, micronaut, java
+	Port         string                   // Exposed port
+	DevMode      bool                     // Development mode
+	Project      string                   // Compose project name, set when generating in an output directory
+	Image        string                   // Image reference when the image is not built by Compose
+	BuildContext string                   // Build context, relative to the output directory
+	Dockerfile   string                   // Dockerfile, relative to the build context, when not in the build context
+	SourceDir    string                   // Source directory mounted in the container, relative to the output directory
+	EnvFile      string                   // Environment file, relative to the output directory
+	Services     []scan.ServiceConfig     // Dependent services as detected, see Dependencies for their compose definitions
+	Watch        []ComposeWatchRule       // develop.watch rules used by docker compose watch
+	DebugPort    string                   // Host port of the JDWP agent, empty when debugging is disabled
+	DebugOptions string                   // JVM options starting the JDWP agent
+	Dependencies []DependencyTemplateData // Dependent services
+}
+
+// newServiceTemplateData builds the template data of an application service
+func newServiceTemplateData(opts Options) ServiceTemplateData {
+	data := ServiceTemplateData{
+		Name:          opts.ServiceName,
+		AppName:       opts.AppName,
+		Path:          filepath.ToSlash(servicePathOf(opts)),
+		Framework:     opts.Framework,
+		Port:          opts.Port,
+		JDKVersion:    opts.JDKVersion,
+		DevMode:       opts.DevMode,
+		BuildSystem:   resolveBuildSystem(opts),
+		BuildStrategy: string(ResolveBuildStrategy(opts)),
+		BuildCommand:  ImageBuildCommand(opts),
+		Context:       serviceDirFromOutput(opts),
+		Dockerfile:    tiltDockerfile(opts),
+		DebugPort:     debugPortOf(opts),
+		Dependencies:  newDependencyTemplateData(opts.Services),
+	}
+	if data.BuildCommand != "" {
+		data.BuildDeps = ImageBuildDeps(opts)
+		data.Image = imageName(opts)
+	}
+	if envFile := getEnvFilePath(servicePathOf(opts)); envFile != "" {
+		data.EnvFile = filepath.ToSlash(relativeToOutput(opts.OutputDir, envFile))
+	}
+	return data
+}
+
+// detectedServices returns the dependent services as the elements of .Services of the single-service Tiltfile
+func detectedServices(services []scan.ServiceConfig) []interface{} {
+	elements := make([]interface{}, len(services))
+	for i, service := range services {
+		elements[i] = service
+	}
+	return elements
+}
+
+// newDependencyTemplateData builds the template data of dependent services, declaring each of them once
+func newDependencyTemplateData(services []scan.ServiceConfig) []DependencyTemplateData {
+	var dependencies []DependencyTemplateData
+	declared := make(map[string]bool)
+
+	for _, service := range services {
+		definitions, _ := dependencyServiceDefinitions(service, "")
+		if len(definitions) == 0 || declared[definitions[0].Name] {
+			continue
+		}
+		declared[definitions[0].Name] = true
+
+		dependencies = append(dependencies, DependencyTemplateData{
+			Name:        definitions[0].Name,
+			Type:        string(service.Type),
+			Image:       definitions[0].Image,
+			Version:     service.Version,
+			Port:        definitions[0].Port,
+			Credentials: service.Credentials,
+		})
+	}
+	return dependencies
+}
+
+// newTiltfileTemplateData builds the data of the single-service Tiltfile template
+func newTiltfileTemplateData(opts Options) TiltfileTemplateData {
+	appName := "app"
+	if opts.AppName == "" {
+		// Use current directory name as default name
+		if cwd, err := os.Getwd(); err == nil {
+			appName = filepath.Base(cwd)
+		}
+	} else {
+		appName = opts.AppName
+	}
+
+	port := DefaultPort
+	if opts.Port != "" {
+		port = opts.Port
+	}
+
+	service := newServiceTemplateData(opts)
+	data := TiltfileTemplateData{
+		Framework:      opts.Framework,
+		AppName:        appName,
+		Port:           port,
+		Date:           time.Now().Format("2006-01-02 15:04:05"),
+		DevMode:        opts.DevMode,
+		Services:       detectedServices(opts.Services),
+		Dependencies:   service.Dependencies,
+		IsMultiService: false,
+		BuildStrategy:  service.BuildStrategy,
+		BuildCommand:   service.BuildCommand,
+		BuildDeps:      service.BuildDeps,
+		Image:          service.Image,
+		Context:        service.Context,
+		Dockerfile:     service.Dockerfile,
+		DebugPort:      service.DebugPort,
+	}
+	return data
+}
+
+// newMultiTiltfileTemplateData builds the data of the multi-service Tiltfile template
+func newMultiTiltfileTemplateData(serviceList ServiceList) TiltfileTemplateData {
+	data := TiltfileTemplateData{
+		Date:           time.Now().Format("2006-01-02 15:04:05"),
+		IsMultiService: true,
+		Observability:  serviceList.Observability.Enabled,
+	}
+
+	data.Gateway = serviceList.Gateway.Enabled
+	if proxies, err := chaosProxies(serviceList); err == nil && len(proxies) > 0 {
+		data.ChaosURL = ChaosURL(serviceList.Chaos)
+	}
+	data.Stubs = stubServiceNames(serviceList)
+
+	var dependencies []scan.ServiceConfig
+	for _, opts := range serviceList.Services {
+		service := newServiceTemplateData(opts)
+		if serviceList.Gateway.Enabled {
+			service.GatewayURL = GatewayURL(serviceList, imageName(opts))
+		}
+		data.Services = append(data.Services, service)
+		dependencies = append(dependencies, opts.Services...)
+	}
+	data.Dependencies = newDependencyTemplateData(dependencies)
+
+	// The seed command runs on the host once the database is up, before the applications
+	if serviceList.Seed.Enabled && serviceList.Seed.Command != "" {
+		data.SeedCommand = serviceList.Seed.Command
+		data.SeedDir = filepath.ToSlash(relativeToOutput(serviceList.OutputDir, "."))
+		if service, recipe, ok := seedDatabase(serviceList); ok {
+			data.SeedDeps = []string{recipe.definition(service).Name}
+		}
+	}
+	return data
+}
+
+// newComposeTemplateData builds the data of the docker-compose.yml templates
+func newComposeTemplateData(opts Options) ComposeTemplateData {
+	data := ComposeTemplateData{
+		ServiceName:  opts.ServiceName,
+		Framework:    opts.Framework,
+		Port:         opts.Port,
+		DevMode:      opts.DevMode,
+		Project:      composeProject(opts.OutputDir),
+		BuildContext: relativeToOutput(opts.OutputDir, "."),
+		SourceDir:    relativeToOutput(opts.OutputDir, "./src"),
+		Dockerfile:   composeDockerfile(opts),
+		Services:     opts.Services,
+		Watch:        composeWatchRules(opts),
+		Dependencies: newDependencyTemplateData(opts.Services),
+	}
+	if data.ServiceName == "" {
+		data.ServiceName = "app"
+	}
+	if data.DebugPort = debugPortOf(opts); data.DebugPort != "" {
+		data.DebugOptions = jdwpOptions()
+	}
+
+	// Images built by Jib or Buildpacks are referenced by name instead of being built by Compose
+	if !UsesDockerfile(opts) {
+		data.Image = imageName(opts)
+	}
+	if envFile := getEnvFilePath("."); envFile != "" {
+		data.EnvFile = relativeToOutput(opts.OutputDir, envFile)
+	}
+	return data
+}
+
+// TemplateData returns the data passed to each template when generating the files of a service,
+// by template. serviceList is nil for a single-service project.
+func TemplateData(opts Options, serviceList *ServiceList) map[string]interface{} {
+	data := map[string]interface{}{
+		"Dockerfile": newServiceTemplateData(opts),
+	}
+
+	if serviceList != nil {
+		data["Tiltfile"] = newMultiTiltfileTemplateData(*serviceList)
+		return data
+	}
+
+	data["Tiltfile"] = newTiltfileTemplateData(opts)
+	if len(opts.Services) == 0 {
+		// docker-compose.yml is only rendered from a template without dependent services
+		data["docker-compose.yml"] = newComposeTemplateData(opts)
+	}
+	return data
+}
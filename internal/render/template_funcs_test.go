@@ -0,0 +1,100 @@
+package render
+
+import (
+	"bytes"
+	"testing"
+	"text/template"
+
+	"turbotilt/internal/scan"
+)
+
+func TestTemplateFuncs(t *testing.T) {
+	t.Setenv("TURBOTILT_TEST_VALUE", "from-env")
+
+	tests := []struct {
+		name     string
+		template string
+		data     interface{}
+		expected string
+	}{
+		{"default on empty", `{{ .Version | default "latest" }}`, DependencyTemplateData{}, "latest"},
+		{"default on value", `{{ .Version | default "latest" }}`, DependencyTemplateData{Version: "16"}, "16"},
+		{"default on zero int", `{{ .Port | default "8080" }}`, ServiceTemplateData{}, "8080"},
+		{"join", `{{ join ", " .BuildDeps }}`, ServiceTemplateData{BuildDeps: []string{"src", "pom.xml"}}, "src, pom.xml"},
+		{"quote", `{{ quote .Name }}`, ServiceTemplateData{Name: "api"}, `"api"`},
+		{"indent", `{{ indent 2 "a\nb" }}`, nil, "  a\n  b"},
+		{"toYaml", `{{ toYaml .Credentials }}`, DependencyTemplateData{Credentials: map[string]string{"POSTGRES_USER": "app"}}, "POSTGRES_USER: app"},
+		{"env", `{{ env "TURBOTILT_TEST_VALUE" }}`, nil, "from-env"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tmpl, err := template.New(test.name).Funcs(templateFuncs()).Parse(test.template)
+			if err != nil {
+				t.Fatalf("Parse returned an error: %v", err)
+			}
+			var buf bytes.Buffer
+			if err := tmpl.Execute(&buf, test.data); err != nil {
+				t.Fatalf("Execute returned an error: %v", err)
+			}
+			if buf.String() != test.expected {
+				t.Errorf("Expected %q, got %q", test.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestTemplateData(t *testing.T) {
+	opts := Options{
+		ServiceName: "api",
+		AppName:     "api",
+		Framework:   "spring",
+		Port:        "8080",
+		JDKVersion:  "17",
+		Path:        ".",
+		OutputDir:   ".turbotilt",
+		Services: []scan.ServiceConfig{
+			{Type: scan.PostgreSQL, Version: "16"},
+			{Type: scan.PostgreSQL, Version: "16"},
+		},
+	}
+
+	data := TemplateData(opts, nil)
+
+	service, ok := data["Dockerfile"].(ServiceTemplateData)
+	if !ok {
+		t.Fatalf("Dockerfile data should be a ServiceTemplateData, got %T", data["Dockerfile"])
+	}
+	if service.Name != "api" || service.JDKVersion != "17" {
+		t.Errorf("Unexpected Dockerfile data: %+v", service)
+	}
+
+	tiltfile, ok := data["Tiltfile"].(TiltfileTemplateData)
+	if !ok {
+		t.Fatalf("Tiltfile data should be a TiltfileTemplateData, got %T", data["Tiltfile"])
+	}
+	if tiltfile.Context != ".." {
+		t.Errorf("Expected the build context relative to the output directory, got %q", tiltfile.Context)
+	}
+	if len(tiltfile.Dependencies) != 1 || tiltfile.Dependencies[0].Version != "16" {
+		t.Errorf("Expected a single postgres dependency, got %+v", tiltfile.Dependencies)
+	}
+
+	// .Services keeps the detected services of a single-service project for the existing templates
+	ts := NewTemplateService()
+	tmpl, err := template.New("custom").Delims("[[", "]]").Funcs(ts.FuncMap).Parse("[[range .Services]][[.Type]]:[[.Version]] [[end]]")
+	if err != nil {
+		t.Fatalf("Unable to parse template: %v", err)
+	}
+	content, err := ts.Render(tmpl, tiltfile)
+	if err != nil {
+		t.Fatalf("Render returned an error: %v", err)
+	}
+	if string(content) != "postgres:16 postgres:16 " {
+		t.Errorf("Unexpected rendering of .Services: %q", content)
+	}
+
+	if _, ok := data["docker-compose.yml"]; ok {
+		t.Error("docker-compose.yml is not rendered from a template with dependent services")
+	}
+}
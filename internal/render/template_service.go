@@ -1,36 +1,73 @@
 package render
 
 import (
+	"bytes"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"text/template"
+
+	"turbotilt/templates"
 )
 
+// ProjectTemplatesDir is the directory of the templates customized for a project, in the output directory
+var ProjectTemplatesDir = filepath.Join(".turbotilt", "templates")
+
+// legacyTemplatesDir is the directory of the project templates before they moved to the output
+// directory. Its templates are still used, with a deprecation warning.
+const legacyTemplatesDir = "templates"
+
+// warnedLegacyTemplates are the legacy templates already reported
+var warnedLegacyTemplates = make(map[string]bool)
+
+// SetProjectTemplatesDir places the project templates in the output directory
+func SetProjectTemplatesDir(outputDir string) {
+	ProjectTemplatesDir = filepath.Join(outputDir, "templates")
+}
+
 // TemplateService provides methods for managing templates
 type TemplateService struct {
 	FuncMap       template.FuncMap
-	TemplatesDirs []string
+	TemplatesDirs []string // Directories searched first, by priority
+	Embedded      fs.FS    // Shipped templates, used when no directory contains the template
 	Delimiters    [2]string
 }
 
 // NewTemplateService creates a new instance of TemplateService
 func NewTemplateService() *TemplateService {
 	return &TemplateService{
-		FuncMap: template.FuncMap{
-			"eq": func(a, b interface{}) bool {
-				return a == b
-			},
-		},
-		TemplatesDirs: []string{
-			"templates",
-			"../templates",
-			".", // For tests that create the template directly
-		},
-		Delimiters: [2]string{"[[", "]]"},
+		FuncMap:       templateFuncs(),
+		TemplatesDirs: TemplateSearchPath(),
+		Embedded:      templates.FS,
+		Delimiters:    [2]string{"[[", "]]"},
 	}
 }
 
+// UserTemplatesDir returns the directory of the templates customized for the user (~/.config/turbotilt/templates)
+func UserTemplatesDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "turbotilt", "templates")
+}
+
+// TemplateSearchPath returns the directories searched for templates: project templates first, then the
+// legacy templates/ directory, then user templates
+func TemplateSearchPath() []string {
+	dirs := []string{ProjectTemplatesDir}
+	if filepath.Clean(ProjectTemplatesDir) != legacyTemplatesDir {
+		dirs = append(dirs, legacyTemplatesDir)
+	}
+	if userDir := UserTemplatesDir(); userDir != "" {
+		dirs = append(dirs, userDir)
+	}
+	return dirs
+}
+
 // FindTemplateFile searches for a template file in the configured directories
 func (ts *TemplateService) FindTemplateFile(baseNames ...string) (string, error) {
 	for _, dir := range ts.TemplatesDirs {
@@ -49,12 +86,26 @@ func (ts *TemplateService) LoadTemplate(name string, templatePaths []string, def
 	// Try to load from files
 	tmplPath, err := ts.FindTemplateFile(templatePaths...)
 	if err == nil {
-		return template.New(name).
+		warnLegacyTemplate(tmplPath)
+		// ParseFiles names the template after the file, so the root template must carry that name
+		return template.New(filepath.Base(tmplPath)).
 			Delims(ts.Delimiters[0], ts.Delimiters[1]).
 			Funcs(ts.FuncMap).
 			ParseFiles(tmplPath)
 	}
 
+	// Then the shipped templates
+	if ts.Embedded != nil {
+		for _, baseName := range templatePaths {
+			if _, statErr := fs.Stat(ts.Embedded, baseName); statErr == nil {
+				return template.New(baseName).
+					Delims(ts.Delimiters[0], ts.Delimiters[1]).
+					Funcs(ts.FuncMap).
+					ParseFS(ts.Embedded, baseName)
+			}
+		}
+	}
+
 	// If not found, use the default template
 	return template.New(name).
 		Delims(ts.Delimiters[0], ts.Delimiters[1]).
@@ -62,18 +113,93 @@ func (ts *TemplateService) LoadTemplate(name string, templatePaths []string, def
 		Parse(defaultTemplate)
 }
 
-// RenderToFile generates a file from a template and data
-func (ts *TemplateService) RenderToFile(filePath string, tmpl *template.Template, data interface{}) error {
-	f, err := os.Create(filePath)
-	if err != nil {
-		return fmt.Errorf("error creating file %s: %w", filePath, err)
+// warnLegacyTemplate reports once a template found in the legacy templates/ directory
+func warnLegacyTemplate(path string) {
+	if filepath.Dir(path) != legacyTemplatesDir || filepath.Clean(ProjectTemplatesDir) == legacyTemplatesDir || warnedLegacyTemplates[path] {
+		return
 	}
-	defer f.Close()
+	warnedLegacyTemplates[path] = true
+	fmt.Printf("⚠️ %s is in the deprecated templates/ directory, move it to %s\n", path, ProjectTemplatesDir)
+}
 
-	err = tmpl.Execute(f, data)
-	if err != nil {
-		return fmt.Errorf("error executing template: %w", err)
+// Render executes a template with the given data and returns the result
+func (ts *TemplateService) Render(tmpl *template.Template, data interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("error executing template: %w", err)
 	}
+	return buf.Bytes(), nil
+}
 
-	return nil
+// TemplateInfo describes a template that can be customized
+type TemplateInfo struct {
+	Name   string // File name of the template
+	Source string // Path of the template in use, or "built-in"
+}
+
+// builtinTemplates returns the content of the templates shipped with Turbotilt, by file name
+func builtinTemplates() map[string]string {
+	builtins := map[string]string{
+		"Dockerfile.spring.tmpl":    SpringDockerfileTmpl,
+		"Dockerfile.quarkus.tmpl":   QuarkusDockerfileTmpl,
+		"Dockerfile.micronaut.tmpl": MicronautDockerfileTmpl,
+		"Dockerfile.java.tmpl":      JavaDockerfileTmpl,
+		"Dockerfile.generic.tmpl":   GenericDockerfileTmpl,
+	}
+
+	entries, _ := fs.ReadDir(templates.FS, ".")
+	for _, entry := range entries {
+		if content, err := fs.ReadFile(templates.FS, entry.Name()); err == nil {
+			builtins[entry.Name()] = string(content)
+		}
+	}
+	return builtins
+}
+
+// ListTemplates returns the templates that can be customized and where the one in use comes from
+func ListTemplates() []TemplateInfo {
+	ts := NewTemplateService()
+	builtins := builtinTemplates()
+
+	names := make([]string, 0, len(builtins))
+	for name := range builtins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	infos := make([]TemplateInfo, 0, len(names))
+	for _, name := range names {
+		info := TemplateInfo{Name: name, Source: "built-in"}
+		if path, err := ts.FindTemplateFile(name); err == nil {
+			info.Source = path
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// EjectTemplate copies a shipped template to the project templates directory, where it overrides
+// the shipped one. An existing project template is only overwritten when force is set.
+func EjectTemplate(name string, force bool) (string, error) {
+	if !strings.HasSuffix(name, ".tmpl") {
+		name += ".tmpl"
+	}
+
+	content, ok := builtinTemplates()[name]
+	if !ok {
+		return "", fmt.Errorf("unknown template %s (see 'turbotilt templates list')", name)
+	}
+
+	path := filepath.Join(ProjectTemplatesDir, name)
+	if _, err := os.Stat(path); err == nil && !force {
+		return "", fmt.Errorf("%s already exists (use --force to overwrite it)", path)
+	}
+
+	if err := os.MkdirAll(ProjectTemplatesDir, 0755); err != nil {
+		return "", fmt.Errorf("error creating directory %s: %w", ProjectTemplatesDir, err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return "", fmt.Errorf("error writing %s: %w", path, err)
+	}
+	return path, nil
 }
@@ -0,0 +1,119 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTemplateLookupOrder(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Unable to get current working directory: %v", err)
+	}
+	defer os.Chdir(oldWd)
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Unable to change directory: %v", err)
+	}
+
+	userDir := filepath.Join(tempDir, "user")
+	ts := NewTemplateService()
+	ts.TemplatesDirs = []string{ProjectTemplatesDir, legacyTemplatesDir, userDir}
+
+	// Without customized template, the shipped one is used
+	tmpl, err := ts.LoadTemplate("Tiltfile", []string{TemplatePathTiltfile}, DefaultTiltfileTemplate)
+	if err != nil {
+		t.Fatalf("LoadTemplate returned an error: %v", err)
+	}
+	content, err := ts.Render(tmpl, TiltfileTemplateData{AppName: "demo", Context: "."})
+	if err != nil {
+		t.Fatalf("Render returned an error: %v", err)
+	}
+	if !strings.Contains(string(content), "APP_NAME = 'demo'") {
+		t.Errorf("The shipped Tiltfile template should be used:\n%s", content)
+	}
+
+	// A user template overrides the shipped one, a legacy templates/ one overrides it, and a project
+	// template overrides them all
+	for _, dir := range []string{userDir, legacyTemplatesDir, ProjectTemplatesDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Unable to create %s: %v", dir, err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, TemplatePathTiltfile), []byte("# from "+dir+"\n"), 0644); err != nil {
+			t.Fatalf("Unable to create template: %v", err)
+		}
+
+		tmpl, err := ts.LoadTemplate("Tiltfile", []string{TemplatePathTiltfile}, DefaultTiltfileTemplate)
+		if err != nil {
+			t.Fatalf("LoadTemplate returned an error: %v", err)
+		}
+		content, _ := ts.Render(tmpl, TiltfileTemplateData{})
+		if string(content) != "# from "+dir+"\n" {
+			t.Errorf("The template of %s should be used, got:\n%s", dir, content)
+		}
+	}
+}
+
+func TestProjectTemplatesDir(t *testing.T) {
+	defer SetProjectTemplatesDir(filepath.Dir(ProjectTemplatesDir))
+
+	// The project templates follow the output directory, the legacy directory is still searched
+	SetProjectTemplatesDir("build/dev")
+	if want := []string{filepath.Join("build", "dev", "templates"), legacyTemplatesDir}; !reflect.DeepEqual(TemplateSearchPath()[:2], want) {
+		t.Errorf("TemplateSearchPath() = %v, want %v first", TemplateSearchPath(), want)
+	}
+
+	// Generating in the project root, both are the same directory
+	SetProjectTemplatesDir(".")
+	if dirs := TemplateSearchPath(); dirs[0] != legacyTemplatesDir || (len(dirs) > 1 && dirs[1] == legacyTemplatesDir) {
+		t.Errorf("templates/ should be searched once, got %v", dirs)
+	}
+}
+
+func TestEjectTemplate(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Unable to get current working directory: %v", err)
+	}
+	defer os.Chdir(oldWd)
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Unable to change directory: %v", err)
+	}
+
+	if _, err := EjectTemplate("Unknown", false); err == nil {
+		t.Error("Ejecting an unknown template should fail")
+	}
+
+	path, err := EjectTemplate("Dockerfile.java", false)
+	if err != nil {
+		t.Fatalf("EjectTemplate returned an error: %v", err)
+	}
+	if path != filepath.Join(ProjectTemplatesDir, "Dockerfile.java.tmpl") {
+		t.Errorf("Unexpected path of the ejected template: %s", path)
+	}
+	if _, err := EjectTemplate("Dockerfile.java.tmpl", false); err == nil {
+		t.Error("Ejecting over an existing template should fail without force")
+	}
+
+	// The ejected template is used to render the Dockerfile
+	if err := os.WriteFile(path, []byte("FROM custom:{{.JDKVersion}}\n"), 0644); err != nil {
+		t.Fatalf("Unable to customize template: %v", err)
+	}
+	var sb strings.Builder
+	if err := defaultRenderer.RenderJavaDockerfile(&sb, Options{JDKVersion: "21"}); err != nil {
+		t.Fatalf("RenderJavaDockerfile returned an error: %v", err)
+	}
+	if sb.String() != "FROM custom:21\n" {
+		t.Errorf("The project template should be used, got:\n%s", sb.String())
+	}
+
+	for _, info := range ListTemplates() {
+		if info.Name == "Dockerfile.java.tmpl" && info.Source != path {
+			t.Errorf("The Java Dockerfile template should come from %s, got %s", path, info.Source)
+		}
+	}
+}
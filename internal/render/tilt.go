@@ -8,17 +8,6 @@ import (
 	"time"
 )
 
-// TiltfileTemplateData contains the data for the Tiltfile template
-type TiltfileTemplateData struct {
-	Framework      string
-	AppName        string
-	Port           string
-	Date           string
-	DevMode        bool
-	Services       []interface{}
-	IsMultiService bool // Indicates if this is a multi-service project
-}
-
 // Base template for multi-service Tiltfile in case the template file doesn't exist
 // Commented out as it's currently not used
 // const tiltfileTemplateMulti = `# Tiltfile generated by Turbotilt
@@ -33,13 +22,33 @@ const (
 # Date: [[.Date]]
 # Framework: [[.Framework]]
 docker_compose('docker-compose.yml')
-`
+[[if .BuildCommand]]
+custom_build(
+  '[[.Image]]',
+  '[[.BuildCommand]]',
+  deps=[
+[[- range .BuildDeps]]
+    '[[.]]',
+[[- end]]
+  ],
+)
+[[end]]`
 
 	// DefaultTiltfileMultiTemplate is the default template for the multi-service Tiltfile
 	DefaultTiltfileMultiTemplate = `# Multi-service Tiltfile generated by Turbotilt (default template)
 # Date: [[.Date]]
 docker_compose('docker-compose.yml')
-`
+[[range .Services]][[if .BuildCommand]]
+custom_build(
+  '[[.Image]]',
+  '[[.BuildCommand]]',
+  deps=[
+[[- range .BuildDeps]]
+    '[[.]]',
+[[- end]]
+  ],
+)
+[[end]][[end]]`
 
 	// TemplatePathTiltfile is the path to the Tiltfile template file
 	TemplatePathTiltfile = "Tiltfile.tmpl"
@@ -53,56 +62,21 @@ func GenerateTiltfile(opts Options) error {
 	// Initialize template service
 	ts := NewTemplateService()
 
-	// Prepare data for the template
-	appName := "app"
-	if opts.AppName == "" {
-		// Use current directory name as default name
-		cwd, err := os.Getwd()
-		if err == nil {
-			appName = filepath.Base(cwd)
-		}
-	} else {
-		appName = opts.AppName
-	}
-
-	port := DefaultPort // Default port
-	if opts.Port != "" {
-		port = opts.Port
-	}
-
-	// Prepare data to inject into the template
-	// Convert services to []interface{} for the template
-	services := make([]interface{}, len(opts.Services))
-	for i, svc := range opts.Services {
-		services[i] = svc
-	}
-
-	data := TiltfileTemplateData{
-		Framework:      opts.Framework,
-		AppName:        appName,
-		Port:           port,
-		Date:           time.Now().Format("2006-01-02 15:04:05"),
-		DevMode:        opts.DevMode,
-		Services:       services,
-		IsMultiService: false,
-	}
-
-	// Default template to use if no template file is found
-	const defaultTemplate = `# Tiltfile generated by Turbotilt (default template)
-# Date: [[.Date]]
-# Framework: [[.Framework]]
-docker_compose('docker-compose.yml')
-`
+	data := newTiltfileTemplateData(opts)
 
 	// Try to load the template
 	tmpl, err := ts.LoadTemplate("Tiltfile",
-		[]string{TemplatePathTiltfile}, defaultTemplate)
+		[]string{TemplatePathTiltfile}, DefaultTiltfileTemplate)
 	if err != nil {
 		return fmt.Errorf("error loading template: %w", err)
 	}
 
 	// Generate the Tiltfile
-	return ts.RenderToFile("Tiltfile", tmpl, data)
+	content, err := ts.Render(tmpl, data)
+	if err != nil {
+		return err
+	}
+	return writeOutputFile(opts.OutputDir, "Tiltfile", content, opts.Force)
 }
 
 // GenerateMultiServiceTiltfile generates a Tiltfile for a multi-service project
@@ -110,24 +84,7 @@ func GenerateMultiServiceTiltfile(serviceList ServiceList) error {
 	// Initialize template service
 	ts := NewTemplateService()
 
-	// Create combined data for all services
-	services := make([]map[string]interface{}, len(serviceList.Services))
-	for i, svc := range serviceList.Services {
-		services[i] = map[string]interface{}{
-			"Name":       svc.ServiceName,
-			"Path":       svc.Path,
-			"Framework":  svc.Framework,
-			"Port":       svc.Port,
-			"DevMode":    svc.DevMode,
-			"JDKVersion": svc.JDKVersion,
-		}
-	}
-
-	data := TiltfileTemplateData{
-		Date:           time.Now().Format("2006-01-02 15:04:05"),
-		Services:       []interface{}{services},
-		IsMultiService: true,
-	}
+	data := newMultiTiltfileTemplateData(serviceList)
 
 	// Try to load the template
 	tmpl, err := ts.LoadTemplate("Tiltfile",
@@ -138,7 +95,19 @@ func GenerateMultiServiceTiltfile(serviceList ServiceList) error {
 	}
 
 	// Generate the Tiltfile
-	return ts.RenderToFile("Tiltfile", tmpl, data)
+	content, err := ts.Render(tmpl, data)
+	if err != nil {
+		return err
+	}
+	return writeOutputFile(serviceList.OutputDir, "Tiltfile", content, serviceList.Force)
+}
+
+// tiltDockerfile returns the Dockerfile of a service relative to the directory of the Tiltfile
+func tiltDockerfile(opts Options) string {
+	if UsesGeneratedDockerfile(opts) {
+		return filepath.ToSlash(relativeToOutput(opts.OutputDir, DockerfilePath(opts)))
+	}
+	return serviceDirFromOutput(opts) + "/Dockerfile"
 }
 
 // GenerateTiltfileFromTemplate generates a customized Tiltfile for testing
@@ -172,3 +172,44 @@ services:
 		t.Error("The generated multi-service Tiltfile should contain 'k8s_yaml'")
 	}
 }
+
+func TestTiltfileDebugLinks(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Unable to get current working directory: %v", err)
+	}
+	defer os.Chdir(oldWd)
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Unable to change directory: %v", err)
+	}
+
+	// The debug port of a single service is shown in Tilt
+	opts := Options{ServiceName: "api", AppName: "api", Framework: "spring", Port: "8080", Path: ".", Debug: true, DebugPort: "5006"}
+	if err := GenerateTiltfile(opts); err != nil {
+		t.Fatalf("GenerateTiltfile returned an error: %v", err)
+	}
+	content, _ := os.ReadFile("Tiltfile")
+	if !strings.Contains(string(content), "dc_resource(APP_NAME, labels=['app'], links=[link('localhost:5006', 'debug (JDWP)')])") {
+		t.Errorf("The debug port should be linked on the application resource:\n%s", content)
+	}
+
+	// In a multi-service project, next to the gateway link
+	serviceList := ServiceList{
+		Gateway: GatewayOptions{Enabled: true},
+		Services: []Options{
+			{ServiceName: "api", Framework: "spring", Port: "8080", Path: ".", Debug: true, DebugPort: "5005"},
+			{ServiceName: "web", Framework: "spring", Port: "8081", Path: "."},
+		},
+	}
+	if err := GenerateMultiServiceTiltfile(serviceList); err != nil {
+		t.Fatalf("GenerateMultiServiceTiltfile returned an error: %v", err)
+	}
+	content, _ = os.ReadFile("Tiltfile")
+	if !strings.Contains(string(content), "dc_resource('api', labels=['app'], links=[link('http://api.localhost', 'api'), link('localhost:5005', 'debug (JDWP)')])") {
+		t.Errorf("The debug port should be linked after the gateway URL:\n%s", content)
+	}
+	if !strings.Contains(string(content), "dc_resource('web', labels=['app'], links=[link('http://web.localhost', 'web')])") {
+		t.Errorf("Unexpected resource of a service without debugging:\n%s", content)
+	}
+}
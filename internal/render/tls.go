@@ -0,0 +1,103 @@
+package render
+
+import (
+	"path"
+	"sort"
+)
+
+const (
+	// CertificatesDir is the folder of the output directory receiving the certificates of the services
+	CertificatesDir = "certs"
+
+	// containerCertificatesDir is where the certificates are mounted in the containers
+	containerCertificatesDir = "/certs"
+
+	// GatewayTLSFile is the Traefik dynamic configuration declaring the gateway certificate
+	GatewayTLSFile = "traefik-tls.yaml"
+
+	// GatewayCertificate is the name of the certificate of the gateway
+	GatewayCertificate = "gateway"
+)
+
+// TLSOptions serves the services over HTTPS with certificates of the development CA:
+// terminated by the gateway when there is one, by the applications otherwise
+type TLSOptions struct {
+	Enabled bool
+}
+
+// CertificateHosts returns, by certificate name, the hostnames each certificate is issued for
+func CertificateHosts(serviceList ServiceList) map[string][]string {
+	hosts := make(map[string][]string)
+
+	if serviceList.Gateway.Enabled {
+		gatewayHosts := []string{"localhost", "127.0.0.1"}
+		for _, opts := range serviceList.Services {
+			gatewayHosts = append(gatewayHosts, GatewayHost(imageName(opts)))
+		}
+		sort.Strings(gatewayHosts[2:])
+		hosts[GatewayCertificate] = gatewayHosts
+		return hosts
+	}
+
+	for _, opts := range serviceList.Services {
+		name := imageName(opts)
+		hosts[name] = []string{name, "localhost", "127.0.0.1"}
+	}
+	return hosts
+}
+
+// certificatesVolume returns the volume mounting the certificates of the output directory
+func certificatesVolume() string {
+	return "./" + CertificatesDir + ":" + containerCertificatesDir + ":ro"
+}
+
+// serveOverTLS mounts the certificate of an application service and configures its framework
+// to serve HTTPS on the service port
+func serveOverTLS(service *ComposeServiceDefinition, opts Options) {
+	name := imageName(opts)
+	certificate := path.Join(containerCertificatesDir, name+".pem")
+	key := path.Join(containerCertificatesDir, name+"-key.pem")
+	port := getOrDefault(opts.Port, DefaultPort)
+
+	service.Volumes = append(service.Volumes, certificatesVolume())
+
+	switch opts.Framework {
+	case "spring":
+		service.Environment["SERVER_SSL_ENABLED"] = "true"
+		service.Environment["SERVER_SSL_CERTIFICATE"] = certificate
+		service.Environment["SERVER_SSL_CERTIFICATE_PRIVATE_KEY"] = key
+	case "quarkus":
+		service.Environment["QUARKUS_HTTP_SSL_PORT"] = port
+		service.Environment["QUARKUS_HTTP_SSL_CERTIFICATE_FILES"] = certificate
+		service.Environment["QUARKUS_HTTP_SSL_CERTIFICATE_KEY_FILES"] = key
+		service.Environment["QUARKUS_HTTP_INSECURE_REQUESTS"] = "disabled"
+	case "micronaut":
+		// Micronaut 4 reads the key and the certificate from a single PEM key store
+		service.Environment["MICRONAUT_SERVER_SSL_ENABLED"] = "true"
+		service.Environment["MICRONAUT_SERVER_SSL_PORT"] = port
+		service.Environment["MICRONAUT_SERVER_SSL_KEY_STORE_PATH"] = "file:" + path.Join(containerCertificatesDir, name+"-bundle.pem")
+		service.Environment["MICRONAUT_SERVER_SSL_KEY_STORE_TYPE"] = "PEM"
+	}
+}
+
+// terminateTLS makes the gateway serve HTTPS with its certificate, the applications staying in HTTP behind it.
+// HTTP requests are redirected to the published HTTPS port, the one the browser can reach
+func terminateTLS(gateway *ComposeServiceDefinition, options GatewayOptions) {
+	tlsPort := getOrDefault(options.TLSPort, DefaultGatewayTLSPort)
+	gateway.Command += " --entrypoints.https.address=:443 --providers.file.filename=/etc/traefik/tls.yaml" +
+		" --entrypoints.http.http.redirections.entrypoint.to=:" + tlsPort + " --entrypoints.http.http.redirections.entrypoint.scheme=https"
+	gateway.ExtraPorts = append(gateway.ExtraPorts, tlsPort+":443")
+	gateway.Volumes = append(gateway.Volumes,
+		certificatesVolume(),
+		"./"+GatewayTLSFile+":/etc/traefik/tls.yaml:ro",
+	)
+}
+
+// generateGatewayTLSConfig writes the Traefik configuration declaring the gateway certificate
+func generateGatewayTLSConfig(serviceList ServiceList) error {
+	content := "tls:\n" +
+		"  certificates:\n" +
+		"    - certFile: " + path.Join(containerCertificatesDir, GatewayCertificate+".pem") + "\n" +
+		"      keyFile: " + path.Join(containerCertificatesDir, GatewayCertificate+"-key.pem") + "\n"
+	return writeOutputFile(serviceList.OutputDir, GatewayTLSFile, []byte(content), serviceList.Force)
+}
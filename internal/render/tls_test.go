@@ -0,0 +1,94 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTLS(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Unable to get current working directory: %v", err)
+	}
+	defer os.Chdir(oldWd)
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Unable to change directory: %v", err)
+	}
+
+	services := []Options{
+		{ServiceName: "web", Framework: "quarkus", Port: "8081", Path: "web", OutputDir: ".turbotilt"},
+		{ServiceName: "api", Framework: "spring", Port: "8080", Path: "api", OutputDir: ".turbotilt"},
+	}
+
+	// Without gateway, the applications serve HTTPS with their own certificate
+	serviceList := ServiceList{OutputDir: ".turbotilt", TLS: TLSOptions{Enabled: true}, Services: services}
+	if err := GenerateMultiServiceCompose(serviceList); err != nil {
+		t.Fatalf("GenerateMultiServiceCompose returned an error: %v", err)
+	}
+	compose, err := os.ReadFile(filepath.Join(".turbotilt", "docker-compose.yml"))
+	if err != nil {
+		t.Fatalf("Unable to read docker-compose.yml: %v", err)
+	}
+	for _, expected := range []string{
+		"- SERVER_SSL_CERTIFICATE=/certs/api.pem",
+		"- SERVER_SSL_CERTIFICATE_PRIVATE_KEY=/certs/api-key.pem",
+		"- QUARKUS_HTTP_SSL_PORT=8081",
+		"- ./certs:/certs:ro",
+	} {
+		if !strings.Contains(string(compose), expected) {
+			t.Errorf("docker-compose.yml should contain %q:\n%s", expected, compose)
+		}
+	}
+	if hosts := CertificateHosts(serviceList); len(hosts) != 2 || len(hosts["api"]) == 0 {
+		t.Errorf("Expected a certificate per service, got %v", hosts)
+	}
+
+	// With a gateway, it terminates HTTPS with a certificate for every hostname
+	serviceList.Gateway = GatewayOptions{Enabled: true}
+	if err := GenerateMultiServiceCompose(serviceList); err != nil {
+		t.Fatalf("GenerateMultiServiceCompose returned an error: %v", err)
+	}
+	compose, err = os.ReadFile(filepath.Join(".turbotilt", "docker-compose.yml"))
+	if err != nil {
+		t.Fatalf("Unable to read docker-compose.yml: %v", err)
+	}
+	for _, expected := range []string{"- '443:443'", "- 'traefik.http.routers.api.tls=true'", "- ./traefik-tls.yaml:/etc/traefik/tls.yaml:ro"} {
+		if !strings.Contains(string(compose), expected) {
+			t.Errorf("docker-compose.yml should contain %q:\n%s", expected, compose)
+		}
+	}
+	if strings.Contains(string(compose), "SERVER_SSL_ENABLED") {
+		t.Errorf("The applications should stay in HTTP behind the gateway:\n%s", compose)
+	}
+	if _, err := os.Stat(filepath.Join(".turbotilt", GatewayTLSFile)); err != nil {
+		t.Errorf("The Traefik TLS configuration should be generated: %v", err)
+	}
+
+	hosts := CertificateHosts(serviceList)
+	expectedHosts := []string{"localhost", "127.0.0.1", "api.localhost", "web.localhost"}
+	if strings.Join(hosts[GatewayCertificate], ",") != strings.Join(expectedHosts, ",") {
+		t.Errorf("Expected the gateway certificate for %v, got %v", expectedHosts, hosts)
+	}
+	if url := GatewayURL(serviceList, "api"); url != "https://api.localhost" {
+		t.Errorf("Expected an HTTPS gateway URL, got %s", url)
+	}
+
+	// HTTP is redirected to the published HTTPS port
+	serviceList.Gateway.TLSPort = "8443"
+	serviceList.Force = true
+	if err := GenerateMultiServiceCompose(serviceList); err != nil {
+		t.Fatalf("GenerateMultiServiceCompose returned an error: %v", err)
+	}
+	compose, err = os.ReadFile(filepath.Join(".turbotilt", "docker-compose.yml"))
+	if err != nil {
+		t.Fatalf("Unable to read docker-compose.yml: %v", err)
+	}
+	for _, expected := range []string{"- '8443:443'", "redirections.entrypoint.to=:8443"} {
+		if !strings.Contains(string(compose), expected) {
+			t.Errorf("docker-compose.yml should contain %q:\n%s", expected, compose)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Administration tools that can be added to the environment
+const (
+	ToolAdminer            = "adminer"
+	ToolPgAdmin            = "pgadmin"
+	ToolMongoExpress       = "mongo-express"
+	ToolRedisInsight       = "redis-insight"
+	ToolKafkaUI            = "kafka-ui"
+	ToolRabbitMQManagement = "rabbitmq-management"
+)
+
+// toolsDir is the folder of the output directory receiving the configuration of the tools
+const toolsDir = "tools"
+
+// toolSpec describes an administration tool and the dependent services it can administrate
+type toolSpec struct {
+	Dependencies []string // Compose services administrated, the first one declared is used
+	Port         string   // Host port of the web interface
+}
+
+var toolSpecs = map[string]toolSpec{
+	ToolAdminer:            {Dependencies: []string{"postgres", "mysql", "mariadb", "sqlserver"}, Port: "9080"},
+	ToolPgAdmin:            {Dependencies: []string{"postgres"}, Port: "5050"},
+	ToolMongoExpress:       {Dependencies: []string{"mongodb"}, Port: "9081"},
+	ToolRedisInsight:       {Dependencies: []string{"redis"}, Port: "5540"},
+	ToolKafkaUI:            {Dependencies: []string{"kafka"}, Port: "9082"},
+	ToolRabbitMQManagement: {Dependencies: []string{"rabbitmq"}, Port: "15672"},
+}
+
+// IsValidTool checks if an administration tool is supported
+func IsValidTool(name string) bool {
+	_, ok := toolSpecs[strings.ToLower(name)]
+	return ok
+}
+
+// ToolNames returns the supported administration tools in alphabetical order
+func ToolNames() []string {
+	return sortedKeys(toolSpecs)
+}
+
+// toolDependency returns the dependent service administrated by a tool, among the declared ones
+func toolDependency(tool string, declared map[string]bool) string {
+	for _, dependency := range toolSpecs[strings.ToLower(tool)].Dependencies {
+		if declared[dependency] {
+			return dependency
+		}
+	}
+	return ""
+}
+
+// addTools adds the administration tools to the compose definitions, connected to the dependent
+// services with their credentials. Tools without a dependent service to administrate are skipped.
+func addTools(definitions []ComposeServiceDefinition, tools []string, outputDir string, force bool) ([]ComposeServiceDefinition, error) {
+	byName := make(map[string]int)
+	declared := make(map[string]bool)
+	for i, definition := range definitions {
+		byName[definition.Name] = i
+		declared[definition.Name] = true
+	}
+
+	for _, tool := range tools {
+		tool = strings.ToLower(tool)
+		dependency := toolDependency(tool, declared)
+		if dependency == "" || declared[tool] {
+			continue
+		}
+		target := definitions[byName[dependency]]
+		port := toolSpecs[tool].Port
+
+		switch tool {
+		case ToolAdminer:
+			definitions = append(definitions, ComposeServiceDefinition{
+				Name:  tool,
+				Image: "adminer:4.8.1",
+				Port:  port + ":8080",
+				Environment: map[string]string{
+					"ADMINER_DEFAULT_SERVER": dependency,
+				},
+				DependsOn: []string{dependency},
+			})
+
+		case ToolPgAdmin:
+			if err := generatePgAdminServers(target, outputDir, force); err != nil {
+				return nil, err
+			}
+			definitions = append(definitions, ComposeServiceDefinition{
+				Name:  tool,
+				Image: "dpage/pgadmin4:8.14",
+				Port:  port + ":80",
+				Environment: map[string]string{
+					"PGADMIN_DEFAULT_EMAIL":                   "admin@example.com",
+					"PGADMIN_DEFAULT_PASSWORD":                "admin",
+					"PGADMIN_CONFIG_SERVER_MODE":              "False",
+					"PGADMIN_CONFIG_MASTER_PASSWORD_REQUIRED": "False",
+					"PGADMIN_SERVER_JSON_FILE":                "/pgadmin4/servers.json",
+				},
+				Volumes:   []string{fmt.Sprintf("./%s/pgadmin-servers.json:/pgadmin4/servers.json:ro", toolsDir)},
+				DependsOn: []string{dependency},
+			})
+
+		case ToolMongoExpress:
+			definitions = append(definitions, ComposeServiceDefinition{
+				Name:  tool,
+				Image: "mongo-express:1.0.2",
+				Port:  port + ":8081",
+				Environment: map[string]string{
+					"ME_CONFIG_MONGODB_URL": mongoURL(target),
+					"ME_CONFIG_BASICAUTH":   "false",
+				},
+				DependsOn: []string{dependency},
+			})
+
+		case ToolRedisInsight:
+			definitions = append(definitions, ComposeServiceDefinition{
+				Name:  tool,
+				Image: "redis/redisinsight:2.64",
+				Port:  port + ":5540",
+				Environment: map[string]string{
+					"RI_REDIS_HOST":  dependency,
+					"RI_REDIS_PORT":  "6379",
+					"RI_REDIS_ALIAS": dependency,
+				},
+				DependsOn: []string{dependency},
+			})
+
+		case ToolKafkaUI:
+			definitions = append(definitions, ComposeServiceDefinition{
+				Name:  tool,
+				Image: "provectuslabs/kafka-ui:v0.7.2",
+				Port:  port + ":8080",
+				Environment: map[string]string{
+					"KAFKA_CLUSTERS_0_NAME":             "local",
+					"KAFKA_CLUSTERS_0_BOOTSTRAPSERVERS": serviceAddress(target),
+				},
+				DependsOn: []string{dependency},
+			})
+
+		case ToolRabbitMQManagement:
+			// The management plugin is part of the RabbitMQ image, only its port is published
+			target.ExtraPorts = append(target.ExtraPorts, port+":15672")
+			definitions[byName[dependency]] = target
+		}
+		declared[tool] = true
+	}
+
+	return definitions, nil
+}
+
+// serviceAddress returns the host:port of a dependent service in the compose network, as its recipe
+// declares it (the internal listener of Kafka...)
+func serviceAddress(service ComposeServiceDefinition) string {
+	recipe, ok := defaultCatalog().Lookup(service.Name)
+	if !ok {
+		return service.Name
+	}
+	return recipe.address(service)
+}
+
+// mongoURL returns the connection URL of a MongoDB service, with its root credentials when set
+func mongoURL(mongo ComposeServiceDefinition) string {
+	user, password := mongo.Environment["MONGO_INITDB_ROOT_USERNAME"], mongo.Environment["MONGO_INITDB_ROOT_PASSWORD"]
+	if user != "" && password != "" {
+		return fmt.Sprintf("mongodb://%s:%s@%s:27017/", user, password, mongo.Name)
+	}
+	return fmt.Sprintf("mongodb://%s:27017/", mongo.Name)
+}
+
+// generatePgAdminServers writes the server list imported by pgAdmin, registering the
+// PostgreSQL service. The password is asked once by pgAdmin and kept in its session.
+func generatePgAdminServers(postgres ComposeServiceDefinition, outputDir string, force bool) error {
+	servers := map[string]interface{}{
+		"Servers": map[string]interface{}{
+			"1": map[string]interface{}{
+				"Name":          postgres.Name,
+				"Group":         "turbotilt",
+				"Host":          postgres.Name,
+				"Port":          5432,
+				"MaintenanceDB": getOrDefault(postgres.Environment["POSTGRES_DB"], "postgres"),
+				"Username":      getOrDefault(postgres.Environment["POSTGRES_USER"], "postgres"),
+				"SSLMode":       "prefer",
+			},
+		},
+	}
+	content, err := json.MarshalIndent(servers, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// pgAdmin only reads plain JSON, the file cannot carry the header of the generated files
+	if err := ensureOutputDir(outputDir); err != nil {
+		return fmt.Errorf("error creating output directory: %w", err)
+	}
+	return writeGeneratedPlainFile(outputPath(outputDir, toolsDir, "pgadmin-servers.json"), append(content, '\n'), force)
+}
+
+// ToolFiles returns the configuration files of the tools, relative to the output directory
+func ToolFiles() []string {
+	return []string{toolsDir + "/pgadmin-servers.json"}
+}
+
+// toolEndpoints returns the web interfaces of the tools added to the environment of the services
+func toolEndpoints(serviceList ServiceList) []Endpoint {
+	declared := make(map[string]bool)
+	for _, opts := range serviceList.Services {
+		for _, service := range opts.Services {
+			definitions, _ := dependencyServiceDefinitions(service, "")
+			for _, definition := range definitions {
+				declared[definition.Name] = true
+			}
+		}
+	}
+
+	var endpoints []Endpoint
+	for _, tool := range serviceList.Tools {
+		tool = strings.ToLower(tool)
+		if toolDependency(tool, declared) == "" {
+			continue
+		}
+		endpoints = append(endpoints, Endpoint{Name: tool, Kind: "tool", URL: "http://localhost:" + toolSpecs[tool].Port})
+	}
+	return endpoints
+}
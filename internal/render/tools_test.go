@@ -0,0 +1,121 @@
+package render
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"turbotilt/internal/scan"
+)
+
+func TestTools(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Unable to get current working directory: %v", err)
+	}
+	defer os.Chdir(oldWd)
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Unable to change directory: %v", err)
+	}
+
+	dependencies := []scan.ServiceConfig{
+		{Type: scan.PostgreSQL, Credentials: map[string]string{"POSTGRES_USER": "shop", "POSTGRES_DB": "orders"}},
+		{Type: scan.RabbitMQ},
+	}
+	serviceList := ServiceList{
+		OutputDir: ".turbotilt",
+		Tools:     []string{ToolPgAdmin, ToolAdminer, ToolRabbitMQManagement, ToolMongoExpress},
+		Services: []Options{{
+			ServiceName: "api",
+			Framework:   "spring",
+			Port:        "8080",
+			Path:        "api",
+			OutputDir:   ".turbotilt",
+			Services:    dependencies,
+		}},
+	}
+	if err := GenerateMultiServiceCompose(serviceList); err != nil {
+		t.Fatalf("GenerateMultiServiceCompose returned an error: %v", err)
+	}
+
+	compose, err := os.ReadFile(filepath.Join(".turbotilt", "docker-compose.yml"))
+	if err != nil {
+		t.Fatalf("Unable to read docker-compose.yml: %v", err)
+	}
+	for _, expected := range []string{
+		"  pgadmin:\n    image: dpage/pgadmin4:8.14\n",
+		"- ./tools/pgadmin-servers.json:/pgadmin4/servers.json:ro",
+		"  adminer:\n",
+		"- ADMINER_DEFAULT_SERVER=postgres",
+		"- '15672:15672'",
+	} {
+		if !strings.Contains(string(compose), expected) {
+			t.Errorf("docker-compose.yml should contain %q:\n%s", expected, compose)
+		}
+	}
+	if strings.Contains(string(compose), "mongo-express") {
+		t.Errorf("mongo-express should be skipped without MongoDB:\n%s", compose)
+	}
+
+	content, err := os.ReadFile(filepath.Join(".turbotilt", "tools", "pgadmin-servers.json"))
+	if err != nil {
+		t.Fatalf("Unable to read the pgAdmin servers: %v", err)
+	}
+	var servers struct {
+		Servers map[string]struct {
+			Host          string
+			Username      string
+			MaintenanceDB string
+		}
+	}
+	if err := json.Unmarshal(content, &servers); err != nil {
+		t.Fatalf("The pgAdmin servers are not valid JSON: %v", err)
+	}
+	if server := servers.Servers["1"]; server.Host != "postgres" || server.Username != "shop" || server.MaintenanceDB != "orders" {
+		t.Errorf("Unexpected pgAdmin server %+v", server)
+	}
+
+	// The server list is protected as the other generated files once edited
+	if err := os.WriteFile(filepath.Join(".turbotilt", "tools", "pgadmin-servers.json"), []byte("{}\n"), 0644); err != nil {
+		t.Fatalf("Unable to edit the pgAdmin servers: %v", err)
+	}
+	var overwriteErr *OverwriteError
+	if err := GenerateMultiServiceCompose(serviceList); !errors.As(err, &overwriteErr) || overwriteErr.Ownership != FileModified {
+		t.Errorf("Expected an OverwriteError on the edited server list, got %v", err)
+	}
+	serviceList.Force = true
+	if err := GenerateMultiServiceCompose(serviceList); err != nil {
+		t.Fatalf("GenerateMultiServiceCompose with force returned an error: %v", err)
+	}
+
+	var tools []string
+	for _, endpoint := range Endpoints(serviceList) {
+		if endpoint.Kind == "tool" {
+			tools = append(tools, endpoint.Name+" "+endpoint.URL)
+		}
+	}
+	expected := []string{"pgadmin http://localhost:5050", "adminer http://localhost:9080", "rabbitmq-management http://localhost:15672"}
+	if strings.Join(tools, ",") != strings.Join(expected, ",") {
+		t.Errorf("Tool endpoints = %v, want %v", tools, expected)
+	}
+}
+
+func TestKafkaUIBootstrap(t *testing.T) {
+	definitions := []ComposeServiceDefinition{defaultCatalog().recipes["kafka"].definition(scan.ServiceConfig{Type: scan.Kafka})}
+	definitions, err := addTools(definitions, []string{ToolKafkaUI}, t.TempDir(), false)
+	if err != nil {
+		t.Fatalf("addTools returned an error: %v", err)
+	}
+	if len(definitions) != 2 {
+		t.Fatalf("Expected kafka-ui next to kafka, got %+v", definitions)
+	}
+
+	// kafka-ui connects to the internal listener declared by the recipe
+	if bootstrap := definitions[1].Environment["KAFKA_CLUSTERS_0_BOOTSTRAPSERVERS"]; bootstrap != "kafka:19092" {
+		t.Errorf("Expected the kafka:19092 listener, got %s", bootstrap)
+	}
+}
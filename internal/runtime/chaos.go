@@ -0,0 +1,162 @@
+package runtime
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// latencyToxic is the name of the toxic delaying the responses of a proxy
+const latencyToxic = "latency"
+
+// ChaosClient drives the proxies of Toxiproxy through its API
+type ChaosClient struct {
+	URL    string // Address of the Toxiproxy API (http://localhost:8474)
+	client http.Client
+}
+
+// ChaosProxy is the state of a proxy of Toxiproxy
+type ChaosProxy struct {
+	Name     string       `json:"name"`
+	Listen   string       `json:"listen"`
+	Upstream string       `json:"upstream"`
+	Enabled  bool         `json:"enabled"`
+	Toxics   []ChaosToxic `json:"toxics"`
+}
+
+// ChaosToxic is a fault applied to the connections of a proxy
+type ChaosToxic struct {
+	Name       string         `json:"name"`
+	Type       string         `json:"type"`
+	Stream     string         `json:"stream"`
+	Toxicity   float64        `json:"toxicity"`
+	Attributes map[string]int `json:"attributes"`
+}
+
+// NewChaosClient returns a client of the Toxiproxy API at url
+func NewChaosClient(url string) *ChaosClient {
+	return &ChaosClient{URL: strings.TrimSuffix(url, "/"), client: http.Client{Timeout: 10 * time.Second}}
+}
+
+// Proxies returns the proxies in alphabetical order
+func (c *ChaosClient) Proxies() ([]ChaosProxy, error) {
+	var proxies map[string]ChaosProxy
+	if err := c.call(http.MethodGet, "/proxies", nil, &proxies); err != nil {
+		return nil, err
+	}
+
+	list := make([]ChaosProxy, 0, len(proxies))
+	for _, proxy := range proxies {
+		list = append(list, proxy)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// Latency delays the responses of the service by latency, more or less jitter
+func (c *ChaosClient) Latency(service string, latency, jitter time.Duration) error {
+	toxic := ChaosToxic{
+		Name:     latencyToxic,
+		Type:     "latency",
+		Stream:   "downstream",
+		Toxicity: 1,
+		Attributes: map[string]int{
+			"latency": int(latency.Milliseconds()),
+			"jitter":  int(jitter.Milliseconds()),
+		},
+	}
+
+	// The toxic of a previous command is replaced
+	err := c.call(http.MethodPost, "/proxies/"+service+"/toxics/"+latencyToxic, map[string]interface{}{"attributes": toxic.Attributes}, nil)
+	if err == nil {
+		return nil
+	}
+	if apiErr, ok := err.(*chaosAPIError); !ok || apiErr.status != http.StatusNotFound {
+		return err
+	}
+	return c.call(http.MethodPost, "/proxies/"+service+"/toxics", toxic, nil)
+}
+
+// Cut closes the connections to the service and refuses the new ones
+func (c *ChaosClient) Cut(service string) error {
+	return c.call(http.MethodPost, "/proxies/"+service, map[string]bool{"enabled": false}, nil)
+}
+
+// Restore enables the proxy of the service again and removes its toxics
+func (c *ChaosClient) Restore(service string) error {
+	var proxy ChaosProxy
+	if err := c.call(http.MethodGet, "/proxies/"+service, nil, &proxy); err != nil {
+		return err
+	}
+	for _, toxic := range proxy.Toxics {
+		if err := c.call(http.MethodDelete, "/proxies/"+service+"/toxics/"+toxic.Name, nil, nil); err != nil {
+			return err
+		}
+	}
+	return c.call(http.MethodPost, "/proxies/"+service, map[string]bool{"enabled": true}, nil)
+}
+
+// Reset enables all the proxies and removes all the toxics
+func (c *ChaosClient) Reset() error {
+	return c.call(http.MethodPost, "/reset", nil, nil)
+}
+
+// chaosAPIError is an error response of the Toxiproxy API
+type chaosAPIError struct {
+	status  int
+	message string
+}
+
+func (e *chaosAPIError) Error() string {
+	return e.message
+}
+
+// call sends a request to the Toxiproxy API, decoding the response in result when set
+func (c *ChaosClient) call(method, path string, body, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.URL+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("Toxiproxy is not reachable at %s, is the environment started? (%w)", c.URL, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		var apiError struct {
+			Error string `json:"error"`
+		}
+		message := resp.Status
+		if json.Unmarshal(data, &apiError) == nil && apiError.Error != "" {
+			message = apiError.Error
+		}
+		return &chaosAPIError{status: resp.StatusCode, message: fmt.Sprintf("%s %s: %s", method, path, message)}
+	}
+
+	if result == nil || len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, result)
+}
@@ -0,0 +1,84 @@
+package runtime
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestChaosClient(t *testing.T) {
+	// Toxiproxy API with a postgres proxy
+	requests := []string{}
+	toxics := map[string]bool{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, strings.TrimSpace(r.Method+" "+r.URL.Path+" "+string(body)))
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/proxies":
+			w.Write([]byte(`{"redis":{"name":"redis","upstream":"redis:6379","enabled":false,"toxics":[]},"postgres":{"name":"postgres","upstream":"postgres:5432","enabled":true,"toxics":[]}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/proxies/postgres":
+			w.Write([]byte(`{"name":"postgres","enabled":true,"toxics":[{"name":"latency","type":"latency"}]}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/proxies/postgres/toxics/latency" && !toxics["latency"]:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"toxic not found","status":404}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/proxies/postgres/toxics":
+			toxics["latency"] = true
+			w.Write(body)
+		case strings.HasPrefix(r.URL.Path, "/proxies/postgres") || r.URL.Path == "/reset":
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"proxy not found","status":404}`))
+		}
+	}))
+	defer server.Close()
+	client := NewChaosClient(server.URL)
+
+	proxies, err := client.Proxies()
+	if err != nil {
+		t.Fatalf("Proxies returned an error: %v", err)
+	}
+	if len(proxies) != 2 || proxies[0].Name != "postgres" || proxies[1].Enabled {
+		t.Errorf("Unexpected proxies: %+v", proxies)
+	}
+
+	// The latency toxic is created, then updated
+	for i := 0; i < 2; i++ {
+		requests = nil
+		if err := client.Latency("postgres", 500*time.Millisecond, 0); err != nil {
+			t.Fatalf("Latency returned an error: %v", err)
+		}
+	}
+	if len(requests) != 1 || requests[0] != `POST /proxies/postgres/toxics/latency {"attributes":{"jitter":0,"latency":500}}` {
+		t.Errorf("Unexpected update of the latency: %v", requests)
+	}
+	if !toxics["latency"] {
+		t.Error("The latency toxic should have been created")
+	}
+
+	requests = nil
+	if err := client.Cut("postgres"); err != nil {
+		t.Fatalf("Cut returned an error: %v", err)
+	}
+	if len(requests) != 1 || requests[0] != `POST /proxies/postgres {"enabled":false}` {
+		t.Errorf("Unexpected cut: %v", requests)
+	}
+
+	// Restoring removes the toxics and enables the proxy
+	requests = nil
+	if err := client.Restore("postgres"); err != nil {
+		t.Fatalf("Restore returned an error: %v", err)
+	}
+	expected := []string{"GET /proxies/postgres", "DELETE /proxies/postgres/toxics/latency", `POST /proxies/postgres {"enabled":true}`}
+	if strings.Join(requests, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected restore: %v", requests)
+	}
+
+	// The errors of the API are reported
+	if err := client.Cut("mysql"); err == nil || !strings.Contains(err.Error(), "proxy not found") {
+		t.Errorf("Cut of an unknown proxy should fail, got %v", err)
+	}
+}
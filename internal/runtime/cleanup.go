@@ -1,19 +1,25 @@
 package runtime
 
 import (
+	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
+
+	"turbotilt/internal/render"
 )
 
-// SetupCleanup configures the cleanup of temporary files on program exit
+// SetupCleanup configures the cleanup of temporary files on program exit.
+// Only files generated by Turbotilt and left unmodified are removed.
 func SetupCleanup(tempFiles []string) {
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 
 	go func() {
 		<-c
-		CleanupTempFiles(tempFiles)
+		if _, err := render.CleanGeneratedFiles(tempFiles); err != nil {
+			fmt.Printf("⚠️ Error cleaning the generated files: %v\n", err)
+		}
 		os.Exit(0)
 	}()
 }
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
 
 	"turbotilt/internal/config"
 )
@@ -24,6 +27,23 @@ type RunOptions struct {
 	Debug       bool   // Debug mode with detailed logs
 	ConfigFile  string // Chemin vers le fichier de configuration à utiliser
 	UseMemory   bool   // Utiliser la configuration stockée en mémoire
+	OutputDir   string // Directory containing the generated files (current directory when empty)
+}
+
+// TiltfileArgs returns the tilt arguments selecting the Tiltfile of the output directory
+func TiltfileArgs(outputDir string) []string {
+	if outputDir == "" || filepath.Clean(outputDir) == "." {
+		return nil
+	}
+	return []string{"-f", filepath.Join(outputDir, "Tiltfile")}
+}
+
+// ComposeFileArgs returns the docker compose arguments selecting the compose file of the output directory
+func ComposeFileArgs(outputDir string) []string {
+	if outputDir == "" || filepath.Clean(outputDir) == "." {
+		return nil
+	}
+	return []string{"-f", filepath.Join(outputDir, "docker-compose.yml")}
 }
 
 // TiltUp launches Tilt with the specified options
@@ -51,7 +71,7 @@ func TiltUp(opts RunOptions) error {
 			fmt.Println("📦 Using services configuration from memory")
 
 			// Générer Dockerfile, docker-compose.yml et Tiltfile à partir de la configuration en mémoire
-			if err := config.GenerateFilesFromMemory(); err != nil {
+			if err := config.GenerateFilesFromMemory(opts.OutputDir); err != nil {
 				fmt.Printf("⚠️ Error generating files from memory: %v\n", err)
 				fmt.Println("Falling back to default behavior...")
 			} else {
@@ -61,7 +81,7 @@ func TiltUp(opts RunOptions) error {
 	}
 
 	fmt.Println("🚀 Starting with Tilt...")
-	args := []string{"up"}
+	args := append([]string{"up"}, TiltfileArgs(opts.OutputDir)...)
 
 	if opts.Debug {
 		args = append(args, "--debug")
@@ -82,7 +102,15 @@ func TiltUp(opts RunOptions) error {
 // ComposeUp launches Docker Compose with the specified options
 func ComposeUp(opts RunOptions) error {
 	fmt.Println("🐳 Starting with Docker Compose...")
-	args := []string{"compose", "up"}
+	args := append([]string{"compose"}, ComposeFileArgs(opts.OutputDir)...)
+
+	// Without Tilt, docker compose watch provides the live reload (it cannot run detached)
+	if !opts.Detached && hasComposeWatch(opts.OutputDir) {
+		fmt.Println("👀 Source changes are synced with docker compose watch")
+		args = append(args, "watch")
+	} else {
+		args = append(args, "up")
+	}
 
 	if opts.Detached {
 		args = append(args, "-d")
@@ -106,6 +134,36 @@ func ComposeUp(opts RunOptions) error {
 	return cmd.Run()
 }
 
+// hasComposeWatch reports whether a service of the compose file of the output directory
+// declares develop.watch rules
+func hasComposeWatch(outputDir string) bool {
+	if outputDir == "" {
+		outputDir = "."
+	}
+	data, err := os.ReadFile(filepath.Join(outputDir, "docker-compose.yml"))
+	if err != nil {
+		return false
+	}
+
+	var compose struct {
+		Services map[string]struct {
+			Develop struct {
+				Watch []interface{} `yaml:"watch"`
+			} `yaml:"develop"`
+		} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(data, &compose); err != nil {
+		return false
+	}
+
+	for _, service := range compose.Services {
+		if len(service.Develop.Watch) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // checkTiltInstalled is the actual implementation of the check
 // Separated to allow mocking in tests
 func checkTiltInstalled() bool {
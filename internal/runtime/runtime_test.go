@@ -131,6 +131,96 @@ func TestComposeUp(t *testing.T) {
 	}
 }
 
+func TestOutputDirArgs(t *testing.T) {
+	// Save the original exec.Command function and restore it after the test
+	origExecCommand := execCommand
+	defer func() { execCommand = origExecCommand }()
+	execCommand = mockExecCommand
+
+	orig := isTiltInstalled
+	defer func() { isTiltInstalled = orig }()
+	isTiltInstalled = func() bool { return true }
+
+	// Tilt uses the Tiltfile of the output directory
+	lastMockCmd = mockCmd{}
+	if err := TiltUp(RunOptions{UseTilt: true, OutputDir: ".turbotilt"}); err != nil {
+		t.Errorf("TiltUp returned an error: %v", err)
+	}
+	expectedArgs := []string{"up", "-f", filepath.Join(".turbotilt", "Tiltfile")}
+	if len(lastMockCmd.args) != len(expectedArgs) {
+		t.Fatalf("Unexpected tilt arguments: %v", lastMockCmd.args)
+	}
+	for i, arg := range expectedArgs {
+		if lastMockCmd.args[i] != arg {
+			t.Errorf("Argument %d should be '%s', but it's '%s'", i, arg, lastMockCmd.args[i])
+		}
+	}
+
+	// Docker Compose uses the compose file of the output directory
+	lastMockCmd = mockCmd{}
+	if err := ComposeUp(RunOptions{OutputDir: ".turbotilt"}); err != nil {
+		t.Errorf("ComposeUp returned an error: %v", err)
+	}
+	expectedArgs = []string{"compose", "-f", filepath.Join(".turbotilt", "docker-compose.yml"), "up"}
+	if len(lastMockCmd.args) != len(expectedArgs) {
+		t.Fatalf("Unexpected docker arguments: %v", lastMockCmd.args)
+	}
+	for i, arg := range expectedArgs {
+		if lastMockCmd.args[i] != arg {
+			t.Errorf("Argument %d should be '%s', but it's '%s'", i, arg, lastMockCmd.args[i])
+		}
+	}
+
+	// The current directory needs no file argument
+	if args := ComposeFileArgs("."); len(args) != 0 {
+		t.Errorf("No compose file argument expected for the current directory, got %v", args)
+	}
+}
+
+func TestComposeWatch(t *testing.T) {
+	origExecCommand := execCommand
+	defer func() { execCommand = origExecCommand }()
+	execCommand = mockExecCommand
+
+	outputDir := t.TempDir()
+	compose := `services:
+  api:
+    build: ..
+    develop:
+      watch:
+        - action: sync
+          path: ../src
+          target: /app/src
+`
+	if err := os.WriteFile(filepath.Join(outputDir, "docker-compose.yml"), []byte(compose), 0644); err != nil {
+		t.Fatalf("Unable to write the compose file: %v", err)
+	}
+
+	// Services declaring develop.watch are started with docker compose watch
+	lastMockCmd = mockCmd{}
+	if err := ComposeUp(RunOptions{OutputDir: outputDir, ServiceName: "api"}); err != nil {
+		t.Errorf("ComposeUp returned an error: %v", err)
+	}
+	expectedArgs := []string{"compose", "-f", filepath.Join(outputDir, "docker-compose.yml"), "watch", "api"}
+	if len(lastMockCmd.args) != len(expectedArgs) {
+		t.Fatalf("Unexpected docker arguments: %v", lastMockCmd.args)
+	}
+	for i, arg := range expectedArgs {
+		if lastMockCmd.args[i] != arg {
+			t.Errorf("Argument %d should be '%s', but it's '%s'", i, arg, lastMockCmd.args[i])
+		}
+	}
+
+	// docker compose watch cannot run detached
+	lastMockCmd = mockCmd{}
+	if err := ComposeUp(RunOptions{OutputDir: outputDir, Detached: true}); err != nil {
+		t.Errorf("ComposeUp returned an error: %v", err)
+	}
+	if lastMockCmd.args[3] != "up" {
+		t.Errorf("Detached mode should use docker compose up, got %v", lastMockCmd.args)
+	}
+}
+
 func TestDryRun(t *testing.T) {
 	// Save the original exec.Command function and restore it after the test
 	origExecCommand := execCommand
@@ -0,0 +1,453 @@
+package runtime
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"turbotilt/internal/render"
+)
+
+// snapshotImage is the image archiving and extracting the volumes
+const snapshotImage = "alpine:3.20"
+
+// snapshotExtension is the extension of the archive of a volume in a snapshot
+const snapshotExtension = ".tar.gz"
+
+// validSnapshotName matches the names usable as a snapshot directory
+var validSnapshotName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Volume is a named volume of the compose file
+type Volume struct {
+	Key        string   // Name of the volume in the compose file (postgres_data)
+	Name       string   // Name of the Docker volume (project_postgres_data)
+	Project    string   // Compose project owning the volume
+	Services   []string // Services mounting the volume
+	Dependency bool     // Only mounted by dependent services, run from the images of the recipes
+}
+
+// Snapshot is a saved state of the volumes
+type Snapshot struct {
+	Name    string
+	Created time.Time
+	Volumes []string // Keys of the archived volumes
+	Size    int64    // Size of the archives in bytes
+}
+
+// SnapshotsDir returns the directory receiving the snapshots of the project
+func SnapshotsDir(outputDir string) string {
+	return filepath.Join(outputDir, "snapshots")
+}
+
+// ComposeVolumes returns the named volumes declared by the compose file of the output directory,
+// with the name Docker Compose gives them
+func ComposeVolumes(outputDir string) ([]Volume, error) {
+	if outputDir == "" {
+		outputDir = "."
+	}
+	composeFile := filepath.Join(outputDir, "docker-compose.yml")
+	data, err := os.ReadFile(composeFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s, run 'turbotilt init' first: %w", composeFile, err)
+	}
+
+	var compose struct {
+		Name     string `yaml:"name"`
+		Services map[string]struct {
+			Image   string        `yaml:"image"`
+			Build   interface{}   `yaml:"build"`
+			Volumes []interface{} `yaml:"volumes"`
+		} `yaml:"services"`
+		Volumes map[string]*struct {
+			Name     string      `yaml:"name"`
+			External interface{} `yaml:"external"`
+		} `yaml:"volumes"`
+	}
+	if err := yaml.Unmarshal(data, &compose); err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %w", composeFile, err)
+	}
+
+	project, err := composeProjectName(outputDir, compose.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Dependent services are the ones run from the image of a recipe, or named after a recipe
+	catalog, _ := render.LoadRecipes()
+	dependency := func(name, image string) bool {
+		if catalog == nil {
+			return false
+		}
+		if _, ok := catalog.Lookup(name); ok {
+			return true
+		}
+		repository, _, _ := strings.Cut(image, ":")
+		for _, recipe := range catalog.Recipes() {
+			if recipe.Image == repository {
+				return true
+			}
+		}
+		return false
+	}
+
+	volumes := []Volume{}
+	for key, definition := range compose.Volumes {
+		volume := Volume{Key: key, Name: project + "_" + key, Project: project}
+		if definition != nil {
+			// External volumes are not owned by the project
+			if external, ok := definition.External.(bool); definition.External != nil && (!ok || external) {
+				continue
+			}
+			if definition.Name != "" {
+				volume.Name = definition.Name
+			}
+		}
+		volume.Dependency = true
+		for service, config := range compose.Services {
+			for _, mount := range config.Volumes {
+				if volumeSource(mount) == key {
+					volume.Services = append(volume.Services, service)
+					if config.Build != nil || !dependency(service, config.Image) {
+						volume.Dependency = false
+					}
+					break
+				}
+			}
+		}
+		if len(volume.Services) == 0 {
+			volume.Dependency = false
+		}
+		sort.Strings(volume.Services)
+		volumes = append(volumes, volume)
+	}
+	sort.Slice(volumes, func(i, j int) bool { return volumes[i].Key < volumes[j].Key })
+	return volumes, nil
+}
+
+// volumeSource returns the source of a volume of a service, in short (source:target)
+// or long syntax
+func volumeSource(mount interface{}) string {
+	switch mount := mount.(type) {
+	case string:
+		source, _, _ := strings.Cut(mount, ":")
+		return source
+	case map[string]interface{}:
+		if source, ok := mount["source"].(string); ok {
+			return source
+		}
+	}
+	return ""
+}
+
+// composeProjectName returns the project name used by Docker Compose: COMPOSE_PROJECT_NAME,
+// the name of the compose file, or the name of its directory
+func composeProjectName(outputDir, name string) (string, error) {
+	if env := os.Getenv("COMPOSE_PROJECT_NAME"); env != "" {
+		name = env
+	}
+	if name == "" {
+		dir, err := filepath.Abs(outputDir)
+		if err != nil {
+			return "", err
+		}
+		name = filepath.Base(dir)
+	}
+
+	// Same normalization as Docker Compose
+	name = regexp.MustCompile(`[^-_a-z0-9]`).ReplaceAllString(strings.ToLower(name), "")
+	return strings.TrimLeft(name, "-_"), nil
+}
+
+// SaveSnapshot archives the named volumes of the dependent services of the compose file of the
+// output directory in a snapshot of snapshotsDir, the volumes of the applications and tools are not
+func SaveSnapshot(outputDir, snapshotsDir, name string, force bool) error {
+	if !validSnapshotName.MatchString(name) {
+		return fmt.Errorf("invalid snapshot name %q (letters, digits, '.', '_' and '-')", name)
+	}
+	volumes, err := DependencyVolumes(outputDir)
+	if err != nil {
+		return err
+	}
+	if len(volumes) == 0 {
+		return fmt.Errorf("the dependent services have no named volume to save")
+	}
+
+	dir := filepath.Join(snapshotsDir, name)
+	_, err = os.Stat(dir)
+	exists := err == nil
+	if exists && !force {
+		return fmt.Errorf("snapshot %s already exists (use --force to replace it)", name)
+	}
+
+	// The archives are written next to the snapshot, which is only replaced once they all succeed.
+	// The leading dot keeps the directory out of the snapshot list.
+	if err := os.MkdirAll(snapshotsDir, 0755); err != nil {
+		return fmt.Errorf("unable to create %s: %w", snapshotsDir, err)
+	}
+	tmpDir, err := os.MkdirTemp(snapshotsDir, "."+name+"-")
+	if err != nil {
+		return fmt.Errorf("unable to create the snapshot directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	if err := os.Chmod(tmpDir, 0755); err != nil {
+		return err
+	}
+	absDir, err := filepath.Abs(tmpDir)
+	if err != nil {
+		return err
+	}
+
+	err = withStoppedServices(outputDir, volumes, func() error {
+		for _, volume := range volumes {
+			fmt.Printf("📦 %s\n", volume.Key)
+			archive := volume.Key + snapshotExtension
+			if err := runDocker("run", "--rm",
+				"-v", volume.Name+":/volume:ro",
+				"-v", absDir+":/snapshot",
+				snapshotImage, "tar", "czf", "/snapshot/"+archive, "-C", "/volume", "."); err != nil {
+				return fmt.Errorf("unable to archive volume %s: %w", volume.Name, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return replaceDir(tmpDir, dir, exists)
+}
+
+// replaceDir renames a directory to a target, moving the existing target aside until the rename
+// succeeds, so that the target is never lost
+func replaceDir(source, target string, exists bool) error {
+	if !exists {
+		return os.Rename(source, target)
+	}
+	old := source + ".old"
+	if err := os.Rename(target, old); err != nil {
+		return fmt.Errorf("unable to replace %s: %w", target, err)
+	}
+	if err := os.Rename(source, target); err != nil {
+		os.Rename(old, target)
+		return fmt.Errorf("unable to replace %s: %w", target, err)
+	}
+	return os.RemoveAll(old)
+}
+
+// RestoreSnapshot replaces the content of the named volumes of the dependent services of the
+// compose file of the output directory with the archives of a snapshot
+func RestoreSnapshot(outputDir, snapshotsDir, name string) error {
+	snapshot, err := findSnapshot(snapshotsDir, name)
+	if err != nil {
+		return err
+	}
+	volumes, err := DependencyVolumes(outputDir)
+	if err != nil {
+		return err
+	}
+	if len(volumes) == 0 {
+		return fmt.Errorf("the dependent services have no named volume to restore")
+	}
+
+	archived := make(map[string]bool)
+	for _, key := range snapshot.Volumes {
+		archived[key] = true
+	}
+	restored := []Volume{}
+	for _, volume := range volumes {
+		if archived[volume.Key] {
+			restored = append(restored, volume)
+			delete(archived, volume.Key)
+		} else {
+			fmt.Printf("⚠️ Volume %s is not in snapshot %s, it is kept\n", volume.Key, name)
+		}
+	}
+	for _, key := range sortedKeys(archived) {
+		fmt.Printf("⚠️ Volume %s of snapshot %s is no longer declared, it is skipped\n", key, name)
+	}
+	if len(restored) == 0 {
+		return fmt.Errorf("snapshot %s has no volume of the environment", name)
+	}
+
+	absDir, err := filepath.Abs(filepath.Join(snapshotsDir, name))
+	if err != nil {
+		return err
+	}
+	return withStoppedServices(outputDir, restored, func() error {
+		for _, volume := range restored {
+			fmt.Printf("📥 %s\n", volume.Key)
+			// Labels of the project, so that Docker Compose adopts a volume created here
+			if err := runDocker("volume", "create",
+				"--label", "com.docker.compose.project="+volume.Project,
+				"--label", "com.docker.compose.volume="+volume.Key,
+				volume.Name); err != nil {
+				return fmt.Errorf("unable to create volume %s: %w", volume.Name, err)
+			}
+			archive := volume.Key + snapshotExtension
+			if err := runDocker("run", "--rm",
+				"-v", volume.Name+":/volume",
+				"-v", absDir+":/snapshot:ro",
+				snapshotImage, "sh", "-c",
+				"find /volume -mindepth 1 -delete && tar xzf /snapshot/"+archive+" -C /volume"); err != nil {
+				return fmt.Errorf("unable to restore volume %s: %w", volume.Name, err)
+			}
+		}
+		return nil
+	})
+}
+
+// ListSnapshots returns the snapshots of snapshotsDir, the most recent first
+func ListSnapshots(snapshotsDir string) ([]Snapshot, error) {
+	entries, err := os.ReadDir(snapshotsDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := []Snapshot{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		snapshot, err := findSnapshot(snapshotsDir, entry.Name())
+		if err != nil {
+			continue
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Created.After(snapshots[j].Created) })
+	return snapshots, nil
+}
+
+// findSnapshot reads the archives of a snapshot
+func findSnapshot(snapshotsDir, name string) (Snapshot, error) {
+	if !validSnapshotName.MatchString(name) {
+		return Snapshot{}, fmt.Errorf("invalid snapshot name %q", name)
+	}
+	dir := filepath.Join(snapshotsDir, name)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("snapshot %s not found in %s", name, snapshotsDir)
+	}
+
+	snapshot := Snapshot{Name: name}
+	for _, entry := range entries {
+		key, ok := strings.CutSuffix(entry.Name(), snapshotExtension)
+		if !ok || entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return Snapshot{}, err
+		}
+		snapshot.Volumes = append(snapshot.Volumes, key)
+		snapshot.Size += info.Size()
+		if info.ModTime().After(snapshot.Created) {
+			snapshot.Created = info.ModTime()
+		}
+	}
+	if len(snapshot.Volumes) == 0 {
+		return Snapshot{}, fmt.Errorf("snapshot %s has no volume archive", name)
+	}
+	return snapshot, nil
+}
+
+// DependencyVolumes returns the named volumes of the compose file of the output directory that
+// are only mounted by dependent services, the data reset removes
+func DependencyVolumes(outputDir string) ([]Volume, error) {
+	volumes, err := ComposeVolumes(outputDir)
+	if err != nil {
+		return nil, err
+	}
+	dependencies := []Volume{}
+	for _, volume := range volumes {
+		if volume.Dependency {
+			dependencies = append(dependencies, volume)
+		}
+	}
+	return dependencies, nil
+}
+
+// ResetData stops the environment of the output directory and removes volumes, the other
+// volumes of the environment are kept
+func ResetData(outputDir string, volumes []Volume) error {
+	args := append([]string{"compose"}, ComposeFileArgs(outputDir)...)
+	if err := runDocker(append(args, "down")...); err != nil {
+		return fmt.Errorf("unable to stop the environment: %w", err)
+	}
+	if len(volumes) == 0 {
+		return nil
+	}
+	names := []string{"volume", "rm", "--force"}
+	for _, volume := range volumes {
+		names = append(names, volume.Name)
+	}
+	if err := runDocker(names...); err != nil {
+		return fmt.Errorf("unable to remove the volumes: %w", err)
+	}
+	return nil
+}
+
+// withStoppedServices stops the running services mounting the volumes while fn runs,
+// so that their files are consistent, then starts them again
+func withStoppedServices(outputDir string, volumes []Volume, fn func() error) error {
+	args := append([]string{"compose"}, ComposeFileArgs(outputDir)...)
+	output, err := execCommand("docker", append(args, "ps", "--services", "--status", "running")...).Output()
+	if err != nil {
+		return fmt.Errorf("unable to list the running services: %w", err)
+	}
+	running := make(map[string]bool)
+	for _, service := range strings.Fields(string(output)) {
+		running[service] = true
+	}
+
+	stopped := []string{}
+	for _, volume := range volumes {
+		for _, service := range volume.Services {
+			if running[service] {
+				stopped = append(stopped, service)
+				delete(running, service)
+			}
+		}
+	}
+	if len(stopped) > 0 {
+		fmt.Printf("⏸️ Stopping %s\n", strings.Join(stopped, ", "))
+		if err := runDocker(append(append(args, "stop"), stopped...)...); err != nil {
+			return fmt.Errorf("unable to stop %s: %w", strings.Join(stopped, ", "), err)
+		}
+	}
+
+	err = fn()
+
+	if len(stopped) > 0 {
+		fmt.Printf("▶️ Starting %s\n", strings.Join(stopped, ", "))
+		if startErr := runDocker(append(append(args, "start"), stopped...)...); startErr != nil && err == nil {
+			err = fmt.Errorf("unable to start %s: %w", strings.Join(stopped, ", "), startErr)
+		}
+	}
+	return err
+}
+
+// runDocker runs a docker command, its errors are printed
+func runDocker(args ...string) error {
+	cmd := execCommand("docker", args...)
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// sortedKeys returns the keys of a map in alphabetical order
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,178 @@
+package runtime
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestComposeVolumes(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	outputDir := t.TempDir()
+	compose := `name: shop
+services:
+  app:
+    build: ../app
+    volumes:
+      - uploads:/uploads
+  postgres:
+    image: postgres:16
+    volumes:
+      - postgres_data:/var/lib/postgresql/data
+      - ../seed:/docker-entrypoint-initdb.d:ro
+  mongodb:
+    image: mongo:7
+    volumes:
+      - type: volume
+        source: mongodb_data
+        target: /data/db
+volumes:
+  postgres_data:
+  mongodb_data:
+    name: shared_mongo
+  uploads:
+  cache:
+    external: true
+`
+	if err := os.WriteFile(filepath.Join(outputDir, "docker-compose.yml"), []byte(compose), 0644); err != nil {
+		t.Fatalf("Unable to write the compose file: %v", err)
+	}
+
+	volumes, err := ComposeVolumes(outputDir)
+	if err != nil {
+		t.Fatalf("ComposeVolumes returned an error: %v", err)
+	}
+	expected := []Volume{
+		{Key: "mongodb_data", Name: "shared_mongo", Project: "shop", Services: []string{"mongodb"}, Dependency: true},
+		{Key: "postgres_data", Name: "shop_postgres_data", Project: "shop", Services: []string{"postgres"}, Dependency: true},
+		{Key: "uploads", Name: "shop_uploads", Project: "shop", Services: []string{"app"}},
+	}
+	if !reflect.DeepEqual(volumes, expected) {
+		t.Errorf("Unexpected volumes:\n%+v\nexpected:\n%+v", volumes, expected)
+	}
+
+	// Without a name, the project is named after the directory of the compose file
+	projectDir := filepath.Join(t.TempDir(), "My.Shop")
+	os.MkdirAll(projectDir, 0755)
+	os.WriteFile(filepath.Join(projectDir, "docker-compose.yml"), []byte("services: {}\nvolumes:\n  redis_data:\n"), 0644)
+	volumes, err = ComposeVolumes(projectDir)
+	if err != nil {
+		t.Fatalf("ComposeVolumes returned an error: %v", err)
+	}
+	if len(volumes) != 1 || volumes[0].Name != "myshop_redis_data" {
+		t.Errorf("Unexpected volumes: %+v", volumes)
+	}
+}
+
+func TestSnapshots(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	origExecCommand := execCommand
+	defer func() { execCommand = origExecCommand }()
+	commands := []string{}
+	failArchives := false
+	execCommand = func(command string, args ...string) *exec.Cmd {
+		commands = append(commands, command+" "+strings.Join(args, " "))
+		if failArchives && len(args) > 0 && args[0] == "run" {
+			return exec.Command("false")
+		}
+		return mockExecCommand(command, args...)
+	}
+
+	outputDir := t.TempDir()
+	compose := "name: shop\nservices:\n  postgres:\n    image: postgres:16\n    volumes:\n      - postgres_data:/var/lib/postgresql/data\n  app:\n    build: ../app\n    volumes:\n      - uploads:/uploads\n  grafana:\n    image: grafana/grafana:11.3.0\n    volumes:\n      - grafana_data:/var/lib/grafana\nvolumes:\n  postgres_data:\n  uploads:\n  grafana_data:\n"
+	if err := os.WriteFile(filepath.Join(outputDir, "docker-compose.yml"), []byte(compose), 0644); err != nil {
+		t.Fatalf("Unable to write the compose file: %v", err)
+	}
+	snapshotsDir := SnapshotsDir(outputDir)
+
+	// Each volume is archived in the snapshot directory
+	if err := SaveSnapshot(outputDir, snapshotsDir, "clean", false); err != nil {
+		t.Fatalf("SaveSnapshot returned an error: %v", err)
+	}
+	archive := commands[len(commands)-1]
+	if !strings.Contains(archive, "-v shop_postgres_data:/volume:ro") || !strings.Contains(archive, "tar czf /snapshot/postgres_data.tar.gz") {
+		t.Errorf("Unexpected archive command: %s", archive)
+	}
+	// The volumes of the applications and the tools are not part of the snapshots
+	if joined := strings.Join(commands, "\n"); strings.Contains(joined, "shop_uploads") || strings.Contains(joined, "shop_grafana_data") {
+		t.Errorf("The volumes of the application and the tools should not be archived:\n%s", joined)
+	}
+
+	// An existing snapshot is only replaced with force
+	if err := SaveSnapshot(outputDir, snapshotsDir, "clean", false); err == nil {
+		t.Error("Saving over an existing snapshot should fail without force")
+	}
+	if err := SaveSnapshot(outputDir, snapshotsDir, "../clean", false); err == nil {
+		t.Error("Invalid snapshot names should be rejected")
+	}
+
+	// The mocked docker writes nothing, the archives are created here
+	os.WriteFile(filepath.Join(snapshotsDir, "clean", "postgres_data.tar.gz"), []byte("archive"), 0644)
+	snapshots, err := ListSnapshots(snapshotsDir)
+	if err != nil {
+		t.Fatalf("ListSnapshots returned an error: %v", err)
+	}
+	if len(snapshots) != 1 || snapshots[0].Name != "clean" || !reflect.DeepEqual(snapshots[0].Volumes, []string{"postgres_data"}) || snapshots[0].Size != 7 {
+		t.Errorf("Unexpected snapshots: %+v", snapshots)
+	}
+
+	// Restoring creates the volume with the labels of the project, then replaces its content
+	commands = nil
+	if err := RestoreSnapshot(outputDir, snapshotsDir, "clean"); err != nil {
+		t.Fatalf("RestoreSnapshot returned an error: %v", err)
+	}
+	joined := strings.Join(commands, "\n")
+	for _, expected := range []string{
+		"docker volume create --label com.docker.compose.project=shop --label com.docker.compose.volume=postgres_data shop_postgres_data",
+		"tar xzf /snapshot/postgres_data.tar.gz -C /volume",
+	} {
+		if !strings.Contains(joined, expected) {
+			t.Errorf("Expected command %q in:\n%s", expected, joined)
+		}
+	}
+	if strings.Contains(joined, "shop_uploads") || strings.Contains(joined, "shop_grafana_data") {
+		t.Errorf("The volumes of the application and the tools should not be restored:\n%s", joined)
+	}
+	if err := RestoreSnapshot(outputDir, snapshotsDir, "missing"); err == nil {
+		t.Error("Restoring an unknown snapshot should fail")
+	}
+
+	// A forced save failing keeps the existing snapshot, a forced save succeeding replaces it
+	archivePath := filepath.Join(snapshotsDir, "clean", "postgres_data.tar.gz")
+	failArchives = true
+	if err := SaveSnapshot(outputDir, snapshotsDir, "clean", true); err == nil {
+		t.Error("SaveSnapshot should fail when an archive fails")
+	}
+	if _, err := os.Stat(archivePath); err != nil {
+		t.Errorf("The existing snapshot should be kept when the save fails: %v", err)
+	}
+	failArchives = false
+	if err := SaveSnapshot(outputDir, snapshotsDir, "clean", true); err != nil {
+		t.Fatalf("SaveSnapshot returned an error: %v", err)
+	}
+	if _, err := os.Stat(archivePath); !os.IsNotExist(err) {
+		t.Error("The replaced snapshot should only hold the new archives")
+	}
+	if entries, _ := os.ReadDir(snapshotsDir); len(entries) != 1 {
+		t.Errorf("Unexpected snapshot directories: %v", entries)
+	}
+
+	// Resetting the data removes the volumes of the dependent services, not the ones of the applications
+	volumes, err := DependencyVolumes(outputDir)
+	if err != nil {
+		t.Fatalf("DependencyVolumes returned an error: %v", err)
+	}
+	if len(volumes) != 1 || volumes[0].Key != "postgres_data" {
+		t.Fatalf("Unexpected dependency volumes: %+v", volumes)
+	}
+	commands = nil
+	if err := ResetData(outputDir, volumes); err != nil {
+		t.Fatalf("ResetData returned an error: %v", err)
+	}
+	if len(commands) != 2 || !strings.HasSuffix(commands[0], " down") || commands[1] != "docker volume rm --force shop_postgres_data" {
+		t.Errorf("Unexpected reset commands: %v", commands)
+	}
+}
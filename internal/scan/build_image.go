@@ -0,0 +1,118 @@
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ImageBuildStrategy represents the way the container image of a service is built
+type ImageBuildStrategy string
+
+const (
+	BuildGeneratedDockerfile ImageBuildStrategy = "dockerfile"          // Dockerfile generated by Turbotilt
+	BuildExistingDockerfile  ImageBuildStrategy = "existing-dockerfile" // Dockerfile provided by the project
+	BuildJib                 ImageBuildStrategy = "jib"                 // Jib Maven/Gradle plugin
+	BuildBuildpacks          ImageBuildStrategy = "buildpacks"          // spring-boot:build-image / bootBuildImage
+)
+
+// ImageBuildInfo contains the result of the image build detection
+type ImageBuildInfo struct {
+	Strategy    ImageBuildStrategy // Detected build strategy
+	BuildSystem string             // Build system (maven, gradle)
+	Dockerfile  string             // Path to the project Dockerfile, if any
+}
+
+// IsValidImageBuildStrategy checks if the given strategy is supported
+func IsValidImageBuildStrategy(strategy string) bool {
+	switch ImageBuildStrategy(strings.ToLower(strategy)) {
+	case BuildGeneratedDockerfile, BuildExistingDockerfile, BuildJib, BuildBuildpacks:
+		return true
+	}
+	return false
+}
+
+// DetectImageBuild detects how the image of the project located at projectPath should be built.
+// An existing Dockerfile always wins, then Jib, then Buildpacks; otherwise a Dockerfile is generated.
+func DetectImageBuild(projectPath string) ImageBuildInfo {
+	info := ImageBuildInfo{
+		Strategy:    BuildGeneratedDockerfile,
+		BuildSystem: DetectBuildSystem(projectPath),
+	}
+
+	dockerfilePath := filepath.Join(projectPath, "Dockerfile")
+	if stat, err := os.Stat(dockerfilePath); err == nil && !stat.IsDir() {
+		info.Strategy = BuildExistingDockerfile
+		info.Dockerfile = dockerfilePath
+		return info
+	}
+
+	return DetectPluginImageBuild(projectPath)
+}
+
+// DetectPluginImageBuild detects an image build configured in the build file (Jib, Buildpacks),
+// ignoring any Dockerfile present in the project
+func DetectPluginImageBuild(projectPath string) ImageBuildInfo {
+	info := ImageBuildInfo{
+		Strategy:    BuildGeneratedDockerfile,
+		BuildSystem: DetectBuildSystem(projectPath),
+	}
+
+	content := strings.ToLower(readBuildFile(projectPath, info.BuildSystem))
+	if content == "" {
+		return info
+	}
+
+	// Jib plugin (jib-maven-plugin or com.google.cloud.tools.jib)
+	if strings.Contains(content, "jib-maven-plugin") || strings.Contains(content, "com.google.cloud.tools.jib") {
+		info.Strategy = BuildJib
+		return info
+	}
+
+	// Cloud Native Buildpacks through the Spring Boot plugin, only when explicitly configured
+	switch info.BuildSystem {
+	case "maven":
+		if strings.Contains(content, "spring-boot-maven-plugin") &&
+			(strings.Contains(content, "<goal>build-image</goal>") || strings.Contains(content, "<image>")) {
+			info.Strategy = BuildBuildpacks
+		}
+	case "gradle":
+		if strings.Contains(content, "bootbuildimage") {
+			info.Strategy = BuildBuildpacks
+		}
+	}
+
+	return info
+}
+
+// DetectBuildSystem returns the build system of the project (maven, gradle) or an empty string
+func DetectBuildSystem(projectPath string) string {
+	if _, err := os.Stat(filepath.Join(projectPath, "pom.xml")); err == nil {
+		return "maven"
+	}
+	for _, file := range []string{"build.gradle", "build.gradle.kts"} {
+		if _, err := os.Stat(filepath.Join(projectPath, file)); err == nil {
+			return "gradle"
+		}
+	}
+	return ""
+}
+
+// readBuildFile returns the content of the build file for the given build system
+func readBuildFile(projectPath, buildSystem string) string {
+	var files []string
+	switch buildSystem {
+	case "maven":
+		files = []string{"pom.xml"}
+	case "gradle":
+		files = []string{"build.gradle", "build.gradle.kts"}
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(filepath.Join(projectPath, file))
+		if err == nil {
+			return string(data)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,87 @@
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectImageBuild(t *testing.T) {
+	tests := []struct {
+		name        string
+		files       map[string]string
+		strategy    ImageBuildStrategy
+		buildSystem string
+	}{
+		{
+			name:        "Plain Maven project",
+			files:       map[string]string{"pom.xml": "<project><artifactId>demo</artifactId></project>"},
+			strategy:    BuildGeneratedDockerfile,
+			buildSystem: "maven",
+		},
+		{
+			name: "Existing Dockerfile",
+			files: map[string]string{
+				"pom.xml":    "<project><plugin><artifactId>jib-maven-plugin</artifactId></plugin></project>",
+				"Dockerfile": "FROM eclipse-temurin:17",
+			},
+			strategy:    BuildExistingDockerfile,
+			buildSystem: "maven",
+		},
+		{
+			name:        "Jib Maven plugin",
+			files:       map[string]string{"pom.xml": "<project><plugin><artifactId>jib-maven-plugin</artifactId></plugin></project>"},
+			strategy:    BuildJib,
+			buildSystem: "maven",
+		},
+		{
+			name:        "Jib Gradle plugin",
+			files:       map[string]string{"build.gradle": "plugins { id 'com.google.cloud.tools.jib' version '3.4.0' }"},
+			strategy:    BuildJib,
+			buildSystem: "gradle",
+		},
+		{
+			name: "Spring Boot build-image",
+			files: map[string]string{"pom.xml": `<project><plugin>
+<artifactId>spring-boot-maven-plugin</artifactId>
+<configuration><image><name>demo</name></image></configuration>
+</plugin></project>`},
+			strategy:    BuildBuildpacks,
+			buildSystem: "maven",
+		},
+		{
+			name:        "Spring Boot plugin without image configuration",
+			files:       map[string]string{"pom.xml": "<project><plugin><artifactId>spring-boot-maven-plugin</artifactId></plugin></project>"},
+			strategy:    BuildGeneratedDockerfile,
+			buildSystem: "maven",
+		},
+		{
+			name:        "Gradle bootBuildImage",
+			files:       map[string]string{"build.gradle.kts": "tasks.named<BootBuildImage>(\"bootBuildImage\") { imageName.set(\"demo\") }"},
+			strategy:    BuildBuildpacks,
+			buildSystem: "gradle",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+					t.Fatalf("Unable to create %s: %v", name, err)
+				}
+			}
+
+			info := DetectImageBuild(dir)
+			if info.Strategy != tt.strategy {
+				t.Errorf("Strategy = %s, want %s", info.Strategy, tt.strategy)
+			}
+			if info.BuildSystem != tt.buildSystem {
+				t.Errorf("BuildSystem = %s, want %s", info.BuildSystem, tt.buildSystem)
+			}
+			if tt.strategy == BuildExistingDockerfile && info.Dockerfile != filepath.Join(dir, "Dockerfile") {
+				t.Errorf("Dockerfile = %s, want %s", info.Dockerfile, filepath.Join(dir, "Dockerfile"))
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package scan
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ExternalURL is the URL of a third-party HTTP API set in a property of the application
+type ExternalURL struct {
+	Property string // Property setting the URL (payments.base-url)
+	URL      string
+	Host     string
+}
+
+// urlPropertyNames are the last segments of the properties holding the URL of an API,
+// lowercased without separators: base-url, baseUrl, base_uri, url, endpoint...
+var urlPropertyNames = map[string]bool{
+	"baseurl":  true,
+	"baseuri":  true,
+	"url":      true,
+	"uri":      true,
+	"endpoint": true,
+}
+
+// frameworkPropertyPrefixes are the namespaces of the frameworks, whose URLs are not third-party
+// APIs, except for the REST clients of Quarkus and Micronaut
+var frameworkPropertyPrefixes = []string{"spring.", "management.", "server.", "logging.", "eureka.", "quarkus.", "micronaut."}
+
+// restClientPropertyPrefixes are the namespaces of the REST clients of the frameworks
+var restClientPropertyPrefixes = []string{"quarkus.rest-client.", "micronaut.http.services."}
+
+// placeholderDefaultPattern matches a property placeholder with a default value, e.g. ${PAYMENTS_URL:https://api.stripe.com}
+var placeholderDefaultPattern = regexp.MustCompile(`^\$\{[^:}]+:(.+)\}$`)
+
+// DetectExternalURLs returns the URLs of third-party HTTP APIs set in the application.properties
+// and application.yml files of a project, sorted by property. URLs of local hosts or of hosts without
+// a domain (other compose services) are ignored.
+func DetectExternalURLs(projectPath string) []ExternalURL {
+	resources := filepath.Join(projectPath, "src", "main", "resources")
+	properties := make(map[string]string)
+	for _, name := range []string{"application.properties", "application.yml", "application.yaml"} {
+		content, err := os.ReadFile(filepath.Join(resources, name))
+		if err != nil {
+			continue
+		}
+		if strings.HasSuffix(name, ".properties") {
+			readProperties(content, properties)
+		} else {
+			var document map[string]interface{}
+			if err := yaml.Unmarshal(content, &document); err == nil {
+				flattenProperties("", document, properties)
+			}
+		}
+	}
+
+	var urls []ExternalURL
+	for property, value := range properties {
+		if !isURLProperty(property) {
+			continue
+		}
+		if match := placeholderDefaultPattern.FindStringSubmatch(value); match != nil {
+			value = match[1]
+		}
+		parsed, err := url.Parse(value)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || !isExternalHost(parsed.Hostname()) {
+			continue
+		}
+		urls = append(urls, ExternalURL{Property: property, URL: value, Host: strings.ToLower(parsed.Hostname())})
+	}
+	sort.Slice(urls, func(i, j int) bool { return urls[i].Property < urls[j].Property })
+	return urls
+}
+
+// isURLProperty reports whether a property holds the URL of an API of the application
+func isURLProperty(property string) bool {
+	lower := strings.ToLower(property)
+	for _, prefix := range frameworkPropertyPrefixes {
+		if strings.HasPrefix(lower, prefix) {
+			restClient := false
+			for _, clientPrefix := range restClientPropertyPrefixes {
+				restClient = restClient || strings.HasPrefix(lower, clientPrefix)
+			}
+			if !restClient {
+				return false
+			}
+		}
+	}
+
+	segments := strings.Split(lower, ".")
+	last := strings.NewReplacer("-", "", "_", "").Replace(segments[len(segments)-1])
+	return urlPropertyNames[last]
+}
+
+// isExternalHost reports whether a host is outside of the environment: not local, and with a domain
+func isExternalHost(host string) bool {
+	if host == "" || strings.EqualFold(host, "localhost") {
+		return false
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return !ip.IsLoopback()
+	}
+	return strings.Contains(host, ".")
+}
+
+// readProperties adds the properties of a .properties file
+func readProperties(content []byte, properties map[string]string) {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		separator := strings.IndexAny(line, "=:")
+		if separator <= 0 {
+			continue
+		}
+		key := strings.TrimSpace(line[:separator])
+		if _, ok := properties[key]; !ok {
+			properties[key] = strings.TrimSpace(line[separator+1:])
+		}
+	}
+}
+
+// flattenProperties adds the scalar values of a YAML document, with their dotted keys
+func flattenProperties(prefix string, document map[string]interface{}, properties map[string]string) {
+	for key, value := range document {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch value := value.(type) {
+		case map[string]interface{}:
+			flattenProperties(key, value, properties)
+		case string:
+			if _, ok := properties[key]; !ok {
+				properties[key] = value
+			}
+		}
+	}
+}
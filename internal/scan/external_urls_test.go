@@ -0,0 +1,67 @@
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDetectExternalURLs(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		expected []ExternalURL
+	}{
+		{
+			name: "Properties",
+			files: map[string]string{
+				"application.properties": `payments.base-url=https://api.stripe.com/v1
+# geo.base-url=https://maps.example.com
+catalog.base-url=http://catalog:8080
+spring.datasource.url=jdbc:postgresql://postgres:5432/app
+spring.cloud.config.uri=https://config.example.com
+quarkus.rest-client.weather.url=${WEATHER_URL:https://api.weather.gov}
+`,
+			},
+			expected: []ExternalURL{
+				{Property: "payments.base-url", URL: "https://api.stripe.com/v1", Host: "api.stripe.com"},
+				{Property: "quarkus.rest-client.weather.url", URL: "https://api.weather.gov", Host: "api.weather.gov"},
+			},
+		},
+		{
+			name: "YAML",
+			files: map[string]string{
+				"application.yml": `shipping:
+  carrier:
+    baseUrl: https://api.ups.com
+  timeout: 5s
+auth:
+  endpoint: http://localhost:9000
+`,
+			},
+			expected: []ExternalURL{
+				{Property: "shipping.carrier.baseUrl", URL: "https://api.ups.com", Host: "api.ups.com"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			resources := filepath.Join(tempDir, "src", "main", "resources")
+			if err := os.MkdirAll(resources, 0755); err != nil {
+				t.Fatalf("Unable to create %s: %v", resources, err)
+			}
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(resources, name), []byte(content), 0644); err != nil {
+					t.Fatalf("Unable to write %s: %v", name, err)
+				}
+			}
+
+			if urls := DetectExternalURLs(tempDir); !reflect.DeepEqual(urls, tt.expected) {
+				t.Errorf("DetectExternalURLs() = %+v, want %+v", urls, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package scan
+
+import "strings"
+
+// DetectMetricsEndpoint returns the path of the Prometheus endpoint exposed by the project
+// through Micrometer (Spring Boot Actuator, Quarkus, Micronaut), or an empty string
+func DetectMetricsEndpoint(projectPath, framework string) string {
+	content := strings.ToLower(readBuildFile(projectPath, DetectBuildSystem(projectPath)))
+	if content == "" {
+		return ""
+	}
+
+	switch framework {
+	case "spring":
+		if strings.Contains(content, "spring-boot-starter-actuator") && strings.Contains(content, "micrometer-registry-prometheus") {
+			return "/actuator/prometheus"
+		}
+	case "quarkus":
+		if strings.Contains(content, "quarkus-micrometer-registry-prometheus") || strings.Contains(content, "quarkus-smallrye-metrics") {
+			return "/q/metrics"
+		}
+	case "micronaut":
+		if strings.Contains(content, "micrometer-registry-prometheus") {
+			return "/prometheus"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,35 @@
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectMetricsEndpoint(t *testing.T) {
+	tests := []struct {
+		name      string
+		file      string
+		content   string
+		framework string
+		endpoint  string
+	}{
+		{"Spring Boot Actuator with Prometheus", "pom.xml", "<artifactId>spring-boot-starter-actuator</artifactId><artifactId>micrometer-registry-prometheus</artifactId>", "spring", "/actuator/prometheus"},
+		{"Spring Boot Actuator without Prometheus", "pom.xml", "<artifactId>spring-boot-starter-actuator</artifactId>", "spring", ""},
+		{"Quarkus Micrometer", "build.gradle", "implementation 'io.quarkus:quarkus-micrometer-registry-prometheus'", "quarkus", "/q/metrics"},
+		{"Micronaut Micrometer", "build.gradle.kts", `implementation("io.micronaut.micrometer:micronaut-micrometer-registry-prometheus")`, "micronaut", "/prometheus"},
+		{"No metrics", "pom.xml", "<project/>", "spring", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, test.file), []byte(test.content), 0644); err != nil {
+				t.Fatalf("Unable to write %s: %v", test.file, err)
+			}
+			if endpoint := DetectMetricsEndpoint(dir, test.framework); endpoint != test.endpoint {
+				t.Errorf("Expected endpoint %q, got %q", test.endpoint, endpoint)
+			}
+		})
+	}
+}
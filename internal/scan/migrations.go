@@ -0,0 +1,77 @@
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Database migration tools
+const (
+	MigrationFlyway    = "flyway"
+	MigrationLiquibase = "liquibase"
+)
+
+// Migrations describes the database migrations of a project
+type Migrations struct {
+	Tool      string // flyway, liquibase
+	Dir       string // Migration directory
+	Changelog string // Liquibase master changelog, relative to the migration directory
+}
+
+// migrationDirs are the default migration directories of the tools
+var migrationDirs = map[string]string{
+	MigrationFlyway:    filepath.Join("src", "main", "resources", "db", "migration"),
+	MigrationLiquibase: filepath.Join("src", "main", "resources", "db", "changelog"),
+}
+
+// liquibaseChangelogs are the names of the Liquibase master changelog, in order of preference
+var liquibaseChangelogs = []string{
+	"db.changelog-master.yaml",
+	"db.changelog-master.yml",
+	"db.changelog-master.xml",
+	"db.changelog-master.json",
+	"db.changelog-master.sql",
+}
+
+// DetectMigrations returns the Flyway or Liquibase migrations of a project, from the tool declared
+// in its build file and its default migration directory. It returns false when none is found.
+func DetectMigrations(projectPath string) (Migrations, bool) {
+	content := strings.ToLower(readBuildFile(projectPath, DetectBuildSystem(projectPath)))
+
+	for _, tool := range []string{MigrationFlyway, MigrationLiquibase} {
+		dir := filepath.Join(projectPath, migrationDirs[tool])
+		if !strings.Contains(content, tool) {
+			continue
+		}
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			continue
+		}
+
+		migrations := Migrations{Tool: tool, Dir: dir}
+		if tool == MigrationLiquibase {
+			migrations.Changelog = LiquibaseChangelog(dir)
+		}
+		return migrations, true
+	}
+	return Migrations{}, false
+}
+
+// LiquibaseChangelog returns the master changelog of a Liquibase migration directory: the
+// db.changelog-master file, or else the first file of the directory
+func LiquibaseChangelog(dir string) string {
+	for _, name := range liquibaseChangelogs {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return name
+		}
+	}
+
+	// The entries are sorted by name
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			return entry.Name()
+		}
+	}
+	return ""
+}
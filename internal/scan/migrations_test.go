@@ -0,0 +1,69 @@
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectMigrations(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		expected Migrations
+		found    bool
+	}{
+		{
+			name: "Flyway",
+			files: map[string]string{
+				"pom.xml": "<dependency><groupId>org.flywaydb</groupId><artifactId>flyway-core</artifactId></dependency>",
+				"src/main/resources/db/migration/V1__init.sql": "CREATE TABLE users (id INT);",
+			},
+			expected: Migrations{Tool: MigrationFlyway, Dir: filepath.Join("src", "main", "resources", "db", "migration")},
+			found:    true,
+		},
+		{
+			name: "Liquibase",
+			files: map[string]string{
+				"build.gradle": "implementation 'org.liquibase:liquibase-core'",
+				"src/main/resources/db/changelog/changes/001-init.yaml":   "databaseChangeLog: []",
+				"src/main/resources/db/changelog/db.changelog-master.xml": "<databaseChangeLog/>",
+			},
+			expected: Migrations{Tool: MigrationLiquibase, Dir: filepath.Join("src", "main", "resources", "db", "changelog"), Changelog: "db.changelog-master.xml"},
+			found:    true,
+		},
+		{
+			name: "Flyway without migrations",
+			files: map[string]string{
+				"pom.xml": "<artifactId>flyway-core</artifactId>",
+			},
+			found: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			for name, content := range tt.files {
+				path := filepath.Join(tempDir, filepath.FromSlash(name))
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatalf("Unable to create %s: %v", filepath.Dir(path), err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatalf("Unable to write %s: %v", name, err)
+				}
+			}
+
+			migrations, found := DetectMigrations(tempDir)
+			if found != tt.found {
+				t.Fatalf("DetectMigrations() found = %v, want %v", found, tt.found)
+			}
+			if tt.found {
+				tt.expected.Dir = filepath.Join(tempDir, tt.expected.Dir)
+			}
+			if migrations != tt.expected {
+				t.Errorf("DetectMigrations() = %+v, want %+v", migrations, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,284 @@
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// seedFilePatterns are the locations of the seed files of the dependent services looked up in a project:
+// scripts run by MongoDB on its first start, and redis-cli commands
+var seedFilePatterns = map[ServiceType][]string{
+	MongoDB: {
+		filepath.Join("mongo", "*.js"),
+		filepath.Join("mongo", "*.sh"),
+		filepath.Join("mongodb", "*.js"),
+		filepath.Join("mongodb", "*.sh"),
+	},
+	Redis: {
+		filepath.Join("redis", "*.redis"),
+	},
+}
+
+// rabbitListenerPattern matches the queues of the Spring AMQP @RabbitListener annotations,
+// e.g. @RabbitListener(queues = "orders") or @RabbitListener(queues = {"orders", "refunds"})
+var rabbitListenerPattern = regexp.MustCompile(`@RabbitListener\s*\([^@)]*?queues\s*=\s*(\{[^}]*\}|"[^"]*")`)
+
+// quotedPattern matches the string literals of an annotation attribute
+var quotedPattern = regexp.MustCompile(`"([^"]+)"`)
+
+// bucketPattern matches the bucket names set in the application.* files, e.g. app.s3.bucket=invoices
+var bucketPattern = regexp.MustCompile(`(?im)bucket(?:[-_]?name)?["']?\s*[:=]\s*["']?([a-z0-9][a-z0-9.-]{2,62})["']?\s*$`)
+
+// awsServices are the AWS services emulated by LocalStack, with the patterns of the names of their
+// resources in the application.* files and the prefix of these resources in the service
+var awsServices = []struct {
+	Name      string
+	Patterns  []*regexp.Regexp
+	Normalize func(string) string
+	Resource  string
+}{
+	{"s3", []*regexp.Regexp{bucketPattern}, strings.ToLower, ""},
+	{"sqs", []*regexp.Regexp{queuePattern, queueURLPattern}, identity, "queue:"},
+	{"sns", []*regexp.Regexp{topicPattern, topicARNPattern}, identity, "topic:"},
+	{"dynamodb", []*regexp.Regexp{tablePattern}, identity, "table:"},
+}
+
+// queuePattern matches the SQS queue names set in the application.* files under a key naming SQS or AWS,
+// e.g. app.aws.orders-queue=orders, so that the queues of a message broker are not taken for SQS ones
+var queuePattern = regexp.MustCompile(`(?im)^\s*[\w.-]*(?:sqs|aws)[\w.-]*queue(?:[-_]?(?:name|url))?["']?\s*[:=]\s*["']?(?:https?://\S+/)?([a-z0-9_-]{1,80})["']?\s*$`)
+
+// queueURLPattern matches the SQS queue URLs set in the application.* files, whose path is the
+// account id and the queue name, e.g. http://localhost:4566/000000000000/orders
+var queueURLPattern = regexp.MustCompile(`(?im)[:=]\s*["']?https?://[^\s/]+/\d{12}/([a-z0-9_-]{1,80})["']?\s*$`)
+
+// topicPattern matches the SNS topic names set in the application.* files under a key naming SNS or AWS,
+// e.g. app.aws.events-topic=events, so that the Kafka topics are not taken for SNS ones
+var topicPattern = regexp.MustCompile(`(?im)^\s*[\w.-]*(?:sns|aws)[\w.-]*topic(?:[-_]?(?:name|arn))?["']?\s*[:=]\s*["']?(?:arn:aws:sns:\S+:)?([a-z0-9_-]{1,256})["']?\s*$`)
+
+// topicARNPattern matches the SNS topic ARNs set in the application.* files,
+// e.g. arn:aws:sns:us-east-1:000000000000:events
+var topicARNPattern = regexp.MustCompile(`(?im)[:=]\s*["']?arn:aws:sns:[a-z0-9-]*:\d{12}:([a-z0-9_-]{1,256})["']?\s*$`)
+
+// sqsListenerPattern matches the queues of the Spring Cloud AWS @SqsListener annotations,
+// e.g. @SqsListener("orders") or @SqsListener(queueNames = {"orders", "refunds"})
+var sqsListenerPattern = regexp.MustCompile(`@SqsListener\s*\(\s*(?:(?:value|queueNames)\s*=\s*)?(\{[^}]*\}|"[^"]*")`)
+
+// sqsListenerImport is the package of the @SqsListener annotation, required in the sources scanned for it
+const sqsListenerImport = "io.awspring.cloud.sqs"
+
+// tablePattern matches the DynamoDB table names set in the application.* files, e.g. app.customers-table=customers
+var tablePattern = regexp.MustCompile(`(?im)table(?:[-_]?name)?["']?\s*[:=]\s*["']?([a-z0-9_.-]{3,255})["']?\s*$`)
+
+// kafkaTopicPattern matches the Kafka topics set in the application.* files, e.g.
+// spring.kafka.template.default-topic=orders or mp.messaging.incoming.orders-in.topic=orders
+var kafkaTopicPattern = regexp.MustCompile(`(?im)topics?(?:[-_]?name)?["']?\s*[:=]\s*["']?([a-z0-9._-]+(?:\s*,\s*[a-z0-9._-]+)*)["']?\s*$`)
+
+// kafkaChannelPattern matches the MicroProfile Reactive Messaging channels of the Kafka connector,
+// whose name is the topic unless the channel sets one
+var kafkaChannelPattern = regexp.MustCompile(`(?im)^\s*mp\.messaging\.(?:incoming|outgoing)\.([a-z0-9._-]+)\.connector\s*[:=]\s*["']?smallrye-kafka["']?\s*$`)
+
+// kafkaChannelTopicPattern matches the MicroProfile Reactive Messaging channels setting their topic
+var kafkaChannelTopicPattern = regexp.MustCompile(`(?im)^\s*mp\.messaging\.(?:incoming|outgoing)\.([a-z0-9._-]+)\.topic\s*[:=]`)
+
+// identity returns a value unchanged
+func identity(value string) string {
+	return value
+}
+
+// KeycloakService returns the Keycloak service of a project, whose recipe imports the realm exports it contains
+func KeycloakService(projectPath string) ServiceConfig {
+	return ServiceConfig{Type: Keycloak, Port: "8180", Dir: projectPath}
+}
+
+// ConfigServerService returns the Spring Cloud Config Server of a project, whose recipe serves its local
+// config repository
+func ConfigServerService(projectPath string) ServiceConfig {
+	return ServiceConfig{Type: ConfigServer, Port: "8888", Dir: projectPath}
+}
+
+// RabbitMQService returns the RabbitMQ service of a project, whose recipe loads its definitions export,
+// creating the queues its @RabbitListener annotations listen to
+func RabbitMQService(projectPath string) ServiceConfig {
+	service := ServiceConfig{Type: RabbitMQ, Port: "5672", Dir: projectPath}
+	for _, queue := range DetectRabbitListenerQueues(projectPath) {
+		service.Resources = append(service.Resources, "queue:"+queue)
+	}
+	return service
+}
+
+// DetectRabbitListenerQueues returns the queues of the @RabbitListener annotations of a project,
+// except the ones set by a property placeholder or an expression
+func DetectRabbitListenerQueues(projectPath string) []string {
+	return listenerQueues(projectPath, rabbitListenerPattern, "")
+}
+
+// DetectSQSListenerQueues returns the queues of the @SqsListener annotations of a project, in the sources
+// importing Spring Cloud AWS SQS, except the ones set by a property placeholder or an expression
+func DetectSQSListenerQueues(projectPath string) []string {
+	return listenerQueues(projectPath, sqsListenerPattern, sqsListenerImport)
+}
+
+// listenerQueues returns the queues of the listener annotations matched by a pattern in the Java and
+// Kotlin sources of a project containing a required import (any source when empty), sorted
+func listenerQueues(projectPath string, pattern *regexp.Regexp, required string) []string {
+	seen := make(map[string]bool)
+	var queues []string
+	for _, sources := range []string{"java", "kotlin"} {
+		root := filepath.Join(projectPath, "src", "main", sources)
+		_ = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() || !(strings.HasSuffix(path, ".java") || strings.HasSuffix(path, ".kt")) {
+				return nil
+			}
+			content, err := os.ReadFile(path)
+			if err != nil || !strings.Contains(string(content), required) {
+				return nil
+			}
+			for _, listener := range pattern.FindAllStringSubmatch(string(content), -1) {
+				for _, quoted := range quotedPattern.FindAllStringSubmatch(listener[1], -1) {
+					queue := quoted[1]
+					if !strings.ContainsAny(queue, "${}#") && !seen[queue] {
+						seen[queue] = true
+						queues = append(queues, queue)
+					}
+				}
+			}
+			return nil
+		})
+	}
+	sort.Strings(queues)
+	return queues
+}
+
+// DetectSeedFiles returns the seed files of a dependent service found in a project
+func DetectSeedFiles(projectPath string, serviceType ServiceType) []string {
+	var seeds []string
+	for _, pattern := range seedFilePatterns[serviceType] {
+		matches, _ := filepath.Glob(filepath.Join(projectPath, pattern))
+		seeds = append(seeds, matches...)
+	}
+	sort.Strings(seeds)
+	return seeds
+}
+
+// DetectBuckets returns the bucket names set in the application.* files of a project
+func DetectBuckets(projectPath string) []string {
+	return propertyValues(projectPath, bucketPattern, strings.ToLower)
+}
+
+// LocalStackService returns the LocalStack service emulating the AWS services used by a project,
+// detected from its dependencies (lowercased content of a build file), with the resources set in
+// its application.* files and the queues of its @SqsListener annotations. It returns false when no
+// emulated AWS service is used.
+func LocalStackService(projectPath, dependencies string) (ServiceConfig, bool) {
+	var used []string
+	var resources []string
+	for _, service := range awsServices {
+		if !containsAny(dependencies, awsModulePatterns(service.Name)...) {
+			continue
+		}
+		used = append(used, service.Name)
+		var names []string
+		for _, pattern := range service.Patterns {
+			names = append(names, propertyValues(projectPath, pattern, service.Normalize)...)
+		}
+		if service.Name == "sqs" {
+			names = append(names, DetectSQSListenerQueues(projectPath)...)
+		}
+		for _, name := range uniqueSorted(names) {
+			resources = append(resources, service.Resource+name)
+		}
+	}
+	if len(used) == 0 {
+		return ServiceConfig{}, false
+	}
+	return ServiceConfig{
+		Type:        LocalStack,
+		Port:        "4566",
+		Credentials: map[string]string{"SERVICES": strings.Join(used, ",")},
+		Resources:   resources,
+	}, true
+}
+
+// DetectKafkaTopics returns the Kafka topics set in the application.* files of a project, from the
+// topic properties (spring.kafka.template.default-topic...) and the Reactive Messaging channels
+func DetectKafkaTopics(projectPath string) []string {
+	seen := make(map[string]bool)
+	var topics []string
+	add := func(topic string) {
+		if topic != "" && !seen[topic] {
+			seen[topic] = true
+			topics = append(topics, topic)
+		}
+	}
+
+	for _, list := range propertyValues(projectPath, kafkaTopicPattern, identity) {
+		for _, topic := range strings.Split(list, ",") {
+			add(strings.TrimSpace(topic))
+		}
+	}
+	withTopic := make(map[string]bool)
+	for _, channel := range propertyValues(projectPath, kafkaChannelTopicPattern, identity) {
+		withTopic[channel] = true
+	}
+	for _, channel := range propertyValues(projectPath, kafkaChannelPattern, identity) {
+		if !withTopic[channel] {
+			add(channel)
+		}
+	}
+	sort.Strings(topics)
+	return topics
+}
+
+// awsModulePatterns returns the patterns of the dependencies using an AWS service: SDK v1 and v2
+// modules, Spring Cloud AWS modules and Quarkus extensions
+func awsModulePatterns(service string) []string {
+	return []string{
+		"aws-java-sdk-" + service,
+		"awssdk:" + service,
+		"<artifactid>" + service + "</artifactid>",
+		"spring-cloud-aws-" + service,
+		"spring-cloud-aws-starter-" + service,
+		"quarkus-amazon-" + service,
+	}
+}
+
+// uniqueSorted returns the values sorted, each of them once
+func uniqueSorted(values []string) []string {
+	seen := make(map[string]bool)
+	var unique []string
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
+
+// propertyValues returns the values matched by a pattern in the application.* files of a project,
+// normalized and sorted, each of them once
+func propertyValues(projectPath string, pattern *regexp.Regexp, normalize func(string) string) []string {
+	matches, _ := filepath.Glob(filepath.Join(projectPath, "src", "main", "resources", "application*"))
+
+	seen := make(map[string]bool)
+	var values []string
+	for _, match := range matches {
+		content, err := os.ReadFile(match)
+		if err != nil {
+			continue
+		}
+		for _, found := range pattern.FindAllStringSubmatch(string(content), -1) {
+			value := normalize(found[1])
+			if !seen[value] {
+				seen[value] = true
+				values = append(values, value)
+			}
+		}
+	}
+	sort.Strings(values)
+	return values
+}
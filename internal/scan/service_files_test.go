@@ -0,0 +1,166 @@
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDetectServiceFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	files := map[string]string{
+		"keycloak/shop-realm.json":                  `{"realm": "shop", "enabled": true}`,
+		"keycloak/users.json":                       `[{"username": "alice"}]`,
+		"src/main/resources/application.yml":        "app:\n  s3:\n    bucket: invoices\n    archive-bucket-name: \"archives\"\n",
+		"src/main/resources/application.properties": "storage.bucket=${BUCKET:reports}\nmedia.bucket=media\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tempDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Unable to create %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Unable to write %s: %v", name, err)
+		}
+	}
+
+	// The realm exports are mounted by the recipe, from the directory of the service
+	if keycloak := KeycloakService(tempDir); keycloak.Dir != tempDir {
+		t.Errorf("Keycloak directory = %q, want %q", keycloak.Dir, tempDir)
+	}
+
+	if buckets, want := DetectBuckets(tempDir), []string{"archives", "invoices", "media"}; !reflect.DeepEqual(buckets, want) {
+		t.Errorf("DetectBuckets() = %v, want %v", buckets, want)
+	}
+}
+
+func TestLocalStackService(t *testing.T) {
+	tempDir := t.TempDir()
+	path := filepath.Join(tempDir, "src", "main", "resources", "application.yml")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Unable to create %s: %v", filepath.Dir(path), err)
+	}
+	content := `app:
+  invoices-bucket: invoices
+  orders-queue-url: http://localhost:4566/000000000000/orders
+  events-topic-arn: arn:aws:sns:us-east-1:000000000000:events
+  customers-table: customers
+  sqs-refunds-queue: refunds
+  # The queues and topics of the message brokers are not SQS or SNS ones
+  rabbit-queue: payments
+  audit-topic: audit
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Unable to write %s: %v", path, err)
+	}
+	listeners := map[string]string{
+		"OrderListener.java": "import io.awspring.cloud.sqs.annotation.SqsListener;\n\n@SqsListener(\"shipments\")\npublic void onShipment(String body) {}",
+		// @SqsListener of another library is ignored
+		"OtherListener.java": "@SqsListener(\"ignored\")\npublic void onMessage(String body) {}",
+	}
+	for name, source := range listeners {
+		path := filepath.Join(tempDir, "src", "main", "java", name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Unable to create %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(source), 0644); err != nil {
+			t.Fatalf("Unable to write %s: %v", path, err)
+		}
+	}
+
+	if _, ok := LocalStackService(tempDir, "<artifactid>spring-boot-starter-web</artifactid>"); ok {
+		t.Error("LocalStack should not be detected without AWS dependencies")
+	}
+
+	dependencies := `implementation 'io.awspring.cloud:spring-cloud-aws-starter-sqs'
+implementation 'software.amazon.awssdk:s3'`
+	localstack, ok := LocalStackService(tempDir, dependencies)
+	if !ok {
+		t.Fatal("LocalStack not detected from the AWS dependencies")
+	}
+	if localstack.Credentials["SERVICES"] != "s3,sqs" {
+		t.Errorf("SERVICES = %q, want s3,sqs", localstack.Credentials["SERVICES"])
+	}
+	// The topics and tables of the services not used are ignored
+	if want := []string{"invoices", "queue:orders", "queue:refunds", "queue:shipments"}; !reflect.DeepEqual(localstack.Resources, want) {
+		t.Errorf("Resources = %v, want %v", localstack.Resources, want)
+	}
+
+	localstack, _ = LocalStackService(tempDir, "aws-java-sdk-sns aws-java-sdk-dynamodb")
+	if want := []string{"topic:events", "table:customers"}; !reflect.DeepEqual(localstack.Resources, want) {
+		t.Errorf("Resources = %v, want %v", localstack.Resources, want)
+	}
+}
+
+func TestDetectKafkaTopics(t *testing.T) {
+	tempDir := t.TempDir()
+	files := map[string]string{
+		"src/main/resources/application.yml": "spring:\n  kafka:\n    template:\n      default-topic: orders\napp:\n  topics: payments, refunds\n",
+		"src/main/resources/application.properties": `mp.messaging.incoming.shipments.connector=smallrye-kafka
+mp.messaging.outgoing.orders-out.connector=smallrye-kafka
+mp.messaging.outgoing.orders-out.topic=orders
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(tempDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Unable to create %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Unable to write %s: %v", name, err)
+		}
+	}
+
+	// The channel setting its topic is not a topic itself
+	if topics, want := DetectKafkaTopics(tempDir), []string{"orders", "payments", "refunds", "shipments"}; !reflect.DeepEqual(topics, want) {
+		t.Errorf("DetectKafkaTopics() = %v, want %v", topics, want)
+	}
+}
+
+func TestRabbitMQServiceAndSeeds(t *testing.T) {
+	tempDir := t.TempDir()
+	files := map[string]string{
+		"rabbitmq/definitions.json": `{"queues": []}`,
+		"src/main/java/com/example/OrderListener.java": `@Component
+class OrderListener {
+    @RabbitListener(queues = "orders")
+    void onOrder(Order order) {}
+
+    @RabbitListener(id = "refunds", queues = {"refunds", "${app.queue}"})
+    void onRefund(Refund refund) {}
+
+    @RabbitListener(queuesToDeclare = @Queue("declared"))
+    void onDeclared(String message) {}
+}`,
+		"mongo/01-users.js":  "db.users.insertOne({name: 'alice'})",
+		"mongo/README.md":    "Seed data",
+		"redis/cache.redis":  "SET greeting hello",
+		"redis/ignored.json": "{}",
+	}
+	for name, content := range files {
+		path := filepath.Join(tempDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Unable to create %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Unable to write %s: %v", name, err)
+		}
+	}
+
+	rabbitmq := RabbitMQService(tempDir)
+	if rabbitmq.Dir != tempDir {
+		t.Errorf("RabbitMQ directory = %q, want %q", rabbitmq.Dir, tempDir)
+	}
+	// The queues set by a placeholder or declared by Spring AMQP are ignored
+	if want := []string{"queue:orders", "queue:refunds"}; !reflect.DeepEqual(rabbitmq.Resources, want) {
+		t.Errorf("RabbitMQ resources = %v, want %v", rabbitmq.Resources, want)
+	}
+
+	if seeds, want := DetectSeedFiles(tempDir, MongoDB), []string{filepath.Join(tempDir, "mongo", "01-users.js")}; !reflect.DeepEqual(seeds, want) {
+		t.Errorf("DetectSeedFiles(mongodb) = %v, want %v", seeds, want)
+	}
+	if seeds, want := DetectSeedFiles(tempDir, Redis), []string{filepath.Join(tempDir, "redis", "cache.redis")}; !reflect.DeepEqual(seeds, want) {
+		t.Errorf("DetectSeedFiles(redis) = %v, want %v", seeds, want)
+	}
+}
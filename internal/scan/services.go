@@ -2,6 +2,7 @@ package scan
 
 import (
 	"os"
+	"path/filepath"
 	"strings"
 )
 
@@ -16,49 +17,101 @@ const (
 	Kafka         ServiceType = "kafka"
 	RabbitMQ      ServiceType = "rabbitmq"
 	ElasticSearch ServiceType = "elasticsearch"
+	Keycloak      ServiceType = "keycloak"
+	MinIO         ServiceType = "minio"
+	Mailpit       ServiceType = "mailpit"
+	MailHog       ServiceType = "mailhog"
+	MariaDB       ServiceType = "mariadb"
+	SQLServer     ServiceType = "sqlserver"
+	Oracle        ServiceType = "oracle"
+	OracleXE      ServiceType = "oracle-xe"
+	Cassandra     ServiceType = "cassandra"
+	Neo4j         ServiceType = "neo4j"
+	LocalStack    ServiceType = "localstack"
+	ConfigServer  ServiceType = "config-server"
+	Eureka        ServiceType = "eureka"
+	Consul        ServiceType = "consul"
 )
 
+// databaseURLPatterns maps the patterns of the connection settings found in the application.* files
+// to the databases they connect to, checked in order
+var databaseURLPatterns = []struct {
+	Pattern string
+	Service ServiceConfig
+}{
+	{"jdbc:mariadb:", ServiceConfig{Type: MariaDB, Port: "3306"}},
+	{"jdbc:sqlserver:", ServiceConfig{Type: SQLServer, Port: "1433"}},
+	{"xepdb1", ServiceConfig{Type: OracleXE, Port: "1521"}},
+	{"jdbc:oracle:", ServiceConfig{Type: Oracle, Port: "1521"}},
+	{"spring.cassandra", ServiceConfig{Type: Cassandra, Port: "9042"}},
+	{"quarkus.cassandra", ServiceConfig{Type: Cassandra, Port: "9042"}},
+	{"datastax-java-driver", ServiceConfig{Type: Cassandra, Port: "9042"}},
+	{"cassandra:", ServiceConfig{Type: Cassandra, Port: "9042"}},
+	{"bolt://", ServiceConfig{Type: Neo4j, Port: "7687"}},
+	{"neo4j://", ServiceConfig{Type: Neo4j, Port: "7687"}},
+}
+
 // ServiceConfig contains the configuration of a detected service
 type ServiceConfig struct {
 	Type        ServiceType
 	Version     string
 	Port        string
 	Credentials map[string]string
+	Volumes     []string          // Project files mounted in the service (source relative to the current directory)
+	Resources   []string          // Resources created when the service starts: buckets, or kind:name (queue:orders...)
+	Seeds       []string          // Project files loaded when the service starts (source relative to the current directory)
+	Dir         string            // Directory of the project files of the service, looked up by the mounts of its recipe
+	Mounts      map[string]string // Project files of the mounts of its recipe, by mount name, instead of the ones looked up
 }
 
-// DetectServices detects the services required for the project
+// DetectServices detects the services required for the project in the current directory
 func DetectServices() ([]ServiceConfig, error) {
+	return DetectServicesIn(".")
+}
+
+// DetectServicesIn detects the services required for the project in projectPath
+func DetectServicesIn(projectPath string) ([]ServiceConfig, error) {
 	var services []ServiceConfig
 
 	// Detect from configuration files
-	configServices, err := detectFromConfigFiles()
+	configServices, err := detectFromConfigFiles(projectPath)
 	if err != nil {
 		return nil, err
 	}
 	services = append(services, configServices...)
 
-	// Detect from dependencies (Maven/Gradle)
-	depServices, err := detectFromDependencies()
+	// Detect from dependencies (Maven/Gradle), the configuration prevailing
+	depServices, err := detectFromDependencies(projectPath)
 	if err != nil {
 		return nil, err
 	}
-	services = append(services, depServices...)
+	configured := make(map[ServiceType]bool)
+	for _, service := range configServices {
+		configured[service.Type] = true
+	}
+	for _, service := range depServices {
+		// The Oracle driver is also the one of Oracle XE
+		if configured[service.Type] || (service.Type == Oracle && configured[OracleXE]) {
+			continue
+		}
+		services = append(services, service)
+	}
 
 	return services, nil
 }
 
 // detectFromConfigFiles detects services from configuration files
-func detectFromConfigFiles() ([]ServiceConfig, error) {
+func detectFromConfigFiles(projectPath string) ([]ServiceConfig, error) {
 	var services []ServiceConfig
 
 	// Check in application.properties
-	propsServices, err := detectFromPropertiesFile("src/main/resources/application.properties")
+	propsServices, err := detectFromPropertiesFile(filepath.Join(projectPath, "src/main/resources/application.properties"))
 	if err == nil {
 		services = append(services, propsServices...)
 	}
 
 	// Check in application.yml
-	yamlServices, err := detectFromYamlFile("src/main/resources/application.yml")
+	yamlServices, err := detectFromYamlFile(filepath.Join(projectPath, "src/main/resources/application.yml"))
 	if err == nil {
 		services = append(services, yamlServices...)
 	}
@@ -80,9 +133,8 @@ func detectFromPropertiesFile(path string) ([]ServiceConfig, error) {
 	// MySQL
 	if hasPropertyPattern(path, "jdbc:mysql") {
 		services = append(services, ServiceConfig{
-			Type:    MySQL,
-			Version: "latest",
-			Port:    "3306",
+			Type: MySQL,
+			Port: "3306",
 			Credentials: map[string]string{
 				"MYSQL_ROOT_PASSWORD": "root",
 				"MYSQL_DATABASE":      "app",
@@ -93,9 +145,8 @@ func detectFromPropertiesFile(path string) ([]ServiceConfig, error) {
 	// PostgreSQL
 	if hasPropertyPattern(path, "jdbc:postgresql") {
 		services = append(services, ServiceConfig{
-			Type:    PostgreSQL,
-			Version: "latest",
-			Port:    "5432",
+			Type: PostgreSQL,
+			Port: "5432",
 			Credentials: map[string]string{
 				"POSTGRES_USER":     "postgres",
 				"POSTGRES_PASSWORD": "postgres",
@@ -107,21 +158,22 @@ func detectFromPropertiesFile(path string) ([]ServiceConfig, error) {
 	// MongoDB
 	if hasPropertyPattern(path, "mongodb://") {
 		services = append(services, ServiceConfig{
-			Type:    MongoDB,
-			Version: "latest",
-			Port:    "27017",
+			Type: MongoDB,
+			Port: "27017",
 		})
 	}
 
 	// Redis
 	if hasPropertyPattern(path, "redis://") || hasPropertyPattern(path, "spring.redis") {
 		services = append(services, ServiceConfig{
-			Type:    Redis,
-			Version: "latest",
-			Port:    "6379",
+			Type: Redis,
+			Port: "6379",
 		})
 	}
 
+	// MariaDB, SQL Server, Oracle, Cassandra, Neo4j
+	services = append(services, detectFromDatabaseURLs(path)...)
+
 	return services, nil
 }
 
@@ -141,9 +193,8 @@ func detectFromYamlFile(path string) ([]ServiceConfig, error) {
 	// PostgreSQL detection
 	if strings.Contains(contentStr, "jdbc:postgresql") {
 		services = append(services, ServiceConfig{
-			Type:    PostgreSQL,
-			Version: "latest",
-			Port:    "5432",
+			Type: PostgreSQL,
+			Port: "5432",
 			Credentials: map[string]string{
 				"POSTGRES_USER":     "postgres",
 				"POSTGRES_PASSWORD": "postgres",
@@ -155,9 +206,8 @@ func detectFromYamlFile(path string) ([]ServiceConfig, error) {
 	// MySQL detection
 	if strings.Contains(contentStr, "jdbc:mysql") {
 		services = append(services, ServiceConfig{
-			Type:    MySQL,
-			Version: "latest",
-			Port:    "3306",
+			Type: MySQL,
+			Port: "3306",
 			Credentials: map[string]string{
 				"MYSQL_ROOT_PASSWORD": "root",
 				"MYSQL_DATABASE":      "app",
@@ -168,26 +218,27 @@ func detectFromYamlFile(path string) ([]ServiceConfig, error) {
 	// MongoDB detection
 	if strings.Contains(contentStr, "mongodb://") {
 		services = append(services, ServiceConfig{
-			Type:    MongoDB,
-			Version: "latest",
-			Port:    "27017",
+			Type: MongoDB,
+			Port: "27017",
 		})
 	}
 
 	// Redis detection
 	if strings.Contains(contentStr, "redis://") || strings.Contains(contentStr, "spring.redis") {
 		services = append(services, ServiceConfig{
-			Type:    Redis,
-			Version: "latest",
-			Port:    "6379",
+			Type: Redis,
+			Port: "6379",
 		})
 	}
 
+	// MariaDB, SQL Server, Oracle, Cassandra, Neo4j
+	services = append(services, detectFromDatabaseURLs(path)...)
+
 	return services, nil
 }
 
 // detectFromDependencies detects services from project dependencies
-func detectFromDependencies() ([]ServiceConfig, error) {
+func detectFromDependencies(projectPath string) ([]ServiceConfig, error) {
 	var services []ServiceConfig
 
 	// Liste of build files to check
@@ -205,6 +256,7 @@ func detectFromDependencies() ([]ServiceConfig, error) {
 
 	// Check each build file for service patterns
 	for _, file := range buildFiles {
+		file = filepath.Join(projectPath, file)
 		if _, err := os.Stat(file); err != nil {
 			continue
 		}
@@ -218,9 +270,8 @@ func detectFromDependencies() ([]ServiceConfig, error) {
 
 		if strings.Contains(content, "mysql") && !contains(MySQL) {
 			services = append(services, ServiceConfig{
-				Type:    MySQL,
-				Version: "latest",
-				Port:    "3306",
+				Type: MySQL,
+				Port: "3306",
 				Credentials: map[string]string{
 					"MYSQL_ROOT_PASSWORD": "root",
 					"MYSQL_DATABASE":      "app",
@@ -230,9 +281,8 @@ func detectFromDependencies() ([]ServiceConfig, error) {
 
 		if (strings.Contains(content, "postgresql") || strings.Contains(content, "postgres")) && !contains(PostgreSQL) {
 			services = append(services, ServiceConfig{
-				Type:    PostgreSQL,
-				Version: "latest",
-				Port:    "5432",
+				Type: PostgreSQL,
+				Port: "5432",
 				Credentials: map[string]string{
 					"POSTGRES_USER":     "postgres",
 					"POSTGRES_PASSWORD": "postgres",
@@ -243,41 +293,99 @@ func detectFromDependencies() ([]ServiceConfig, error) {
 
 		if strings.Contains(content, "mongodb") && !contains(MongoDB) {
 			services = append(services, ServiceConfig{
-				Type:    MongoDB,
-				Version: "latest",
-				Port:    "27017",
+				Type:  MongoDB,
+				Port:  "27017",
+				Seeds: DetectSeedFiles(projectPath, MongoDB),
 			})
 		}
 
 		if strings.Contains(content, "redis") && !contains(Redis) {
 			services = append(services, ServiceConfig{
-				Type:    Redis,
-				Version: "latest",
-				Port:    "6379",
+				Type:  Redis,
+				Port:  "6379",
+				Seeds: DetectSeedFiles(projectPath, Redis),
 			})
 		}
 
 		if strings.Contains(content, "kafka") && !contains(Kafka) {
+			service := ServiceConfig{
+				Type: Kafka,
+				Port: "9092",
+			}
+			for _, topic := range DetectKafkaTopics(projectPath) {
+				service.Resources = append(service.Resources, "topic:"+topic)
+			}
+			services = append(services, service)
+		}
+
+		if strings.Contains(content, "rabbitmq") && !contains(RabbitMQ) {
+			services = append(services, RabbitMQService(projectPath))
+		}
+
+		if containsAny(content, "spring-boot-starter-oauth2", "quarkus-oidc", "keycloak") && !contains(Keycloak) {
+			services = append(services, KeycloakService(projectPath))
+		}
+
+		// S3 alone is served by MinIO, LocalStack is only added when other AWS services are used
+		localstack, usesAWS := LocalStackService(projectPath, content)
+		s3Only := usesAWS && localstack.Credentials["SERVICES"] == "s3"
+		if (containsAny(content, "io.minio", "quarkus-minio") || s3Only) && !contains(MinIO) {
 			services = append(services, ServiceConfig{
-				Type:    Kafka,
-				Version: "latest",
-				Port:    "9092",
+				Type:      MinIO,
+				Port:      "9000",
+				Resources: DetectBuckets(projectPath),
 			})
 		}
 
-		if strings.Contains(content, "rabbitmq") && !contains(RabbitMQ) {
+		if usesAWS && !s3Only && !contains(LocalStack) {
+			services = append(services, localstack)
+		}
+
+		if containsAny(content, "spring-boot-starter-mail", "quarkus-mailer", "micronaut-email") && !contains(Mailpit) {
 			services = append(services, ServiceConfig{
-				Type:    RabbitMQ,
-				Version: "latest",
-				Port:    "5672",
+				Type: Mailpit,
+				Port: "1025",
 			})
 		}
 
+		if containsAny(content, "mariadb-java-client", "org.mariadb") && !contains(MariaDB) {
+			services = append(services, ServiceConfig{Type: MariaDB, Port: "3306"})
+		}
+
+		if containsAny(content, "mssql-jdbc", "quarkus-jdbc-mssql") && !contains(SQLServer) {
+			services = append(services, ServiceConfig{Type: SQLServer, Port: "1433"})
+		}
+
+		if containsAny(content, "ojdbc", "quarkus-jdbc-oracle") && !contains(Oracle) {
+			services = append(services, ServiceConfig{Type: Oracle, Port: "1521"})
+		}
+
+		if containsAny(content, "cassandra", "com.datastax.oss") && !contains(Cassandra) {
+			services = append(services, ServiceConfig{Type: Cassandra, Port: "9042"})
+		}
+
+		if strings.Contains(content, "neo4j") && !contains(Neo4j) {
+			services = append(services, ServiceConfig{Type: Neo4j, Port: "7687"})
+		}
+
+		// Spring Cloud infrastructure: the applications fetch their configuration and register in a registry
+		if containsAny(content, "spring-cloud-starter-config", "quarkus-spring-cloud-config-client") && !contains(ConfigServer) &&
+			!strings.Contains(content, "spring-cloud-config-server") {
+			services = append(services, ConfigServerService(projectPath))
+		}
+
+		if strings.Contains(content, "spring-cloud-starter-netflix-eureka-client") && !contains(Eureka) {
+			services = append(services, ServiceConfig{Type: Eureka, Port: "8761"})
+		}
+
+		if containsAny(content, "spring-cloud-starter-consul", "quarkus-config-consul", "stork-service-discovery-consul") && !contains(Consul) {
+			services = append(services, ServiceConfig{Type: Consul, Port: "8500"})
+		}
+
 		if strings.Contains(content, "elasticsearch") && !contains(ElasticSearch) {
 			services = append(services, ServiceConfig{
-				Type:    ElasticSearch,
-				Version: "latest",
-				Port:    "9200",
+				Type: ElasticSearch,
+				Port: "9200",
 			})
 		}
 	}
@@ -285,6 +393,40 @@ func detectFromDependencies() ([]ServiceConfig, error) {
 	return services, nil
 }
 
+// detectFromDatabaseURLs detects the databases whose connection settings are in an application.* file,
+// each of them once
+func detectFromDatabaseURLs(path string) []ServiceConfig {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	lower := strings.ToLower(string(content))
+
+	var services []ServiceConfig
+	detected := make(map[ServiceType]bool)
+	for _, database := range databaseURLPatterns {
+		// Oracle XE is detected from its pluggable database, before the generic Oracle URL
+		if detected[database.Service.Type] || (database.Service.Type == Oracle && detected[OracleXE]) {
+			continue
+		}
+		if strings.Contains(lower, database.Pattern) {
+			detected[database.Service.Type] = true
+			services = append(services, database.Service)
+		}
+	}
+	return services
+}
+
+// containsAny checks if the content contains one of the patterns
+func containsAny(content string, patterns ...string) bool {
+	for _, pattern := range patterns {
+		if strings.Contains(content, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
 // hasPropertyPattern checks if a file contains a certain pattern
 func hasPropertyPattern(path string, pattern string) bool {
 	content, err := os.ReadFile(path)
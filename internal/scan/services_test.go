@@ -243,7 +243,145 @@ services:
 		os.Remove("build.gradle")
 	})
 
-	// Test 6: No service
+	// Test 6: Identity, storage and mail dependencies
+	t.Run("Keycloak, MinIO and mail from Gradle", func(t *testing.T) {
+		gradleContent := `dependencies {
+    implementation 'org.springframework.boot:spring-boot-starter-oauth2-resource-server'
+    implementation 'io.minio:minio:8.5.10'
+    implementation 'org.springframework.boot:spring-boot-starter-mail'
+}`
+
+		if err := os.WriteFile("build.gradle", []byte(gradleContent), 0644); err != nil {
+			t.Fatalf("Error during build.gradle creation: %v", err)
+		}
+		defer os.Remove("build.gradle")
+
+		services, err := DetectServices()
+		if err != nil {
+			t.Errorf("Error during the services scan: %v", err)
+		}
+
+		found := make(map[ServiceType]bool)
+		for _, s := range services {
+			found[s.Type] = true
+		}
+		for _, serviceType := range []ServiceType{Keycloak, MinIO, Mailpit} {
+			if !found[serviceType] {
+				t.Errorf("%s Service not detected using build.gradle", serviceType)
+			}
+		}
+	})
+
+	// Test 6b: S3 alone stays on MinIO, LocalStack emulates S3 along with the other AWS services
+	t.Run("MinIO for the S3 SDK alone", func(t *testing.T) {
+		for _, tt := range []struct {
+			dependencies string
+			want, absent ServiceType
+		}{
+			{"implementation 'software.amazon.awssdk:s3:2.25.0'", MinIO, LocalStack},
+			{"implementation 'software.amazon.awssdk:s3:2.25.0'\n    implementation 'software.amazon.awssdk:sqs:2.25.0'", LocalStack, MinIO},
+		} {
+			if err := os.WriteFile("build.gradle", []byte("dependencies {\n    "+tt.dependencies+"\n}"), 0644); err != nil {
+				t.Fatalf("Error during build.gradle creation: %v", err)
+			}
+
+			services, err := DetectServices()
+			if err != nil {
+				t.Errorf("Error during the services scan: %v", err)
+			}
+			found := make(map[ServiceType]bool)
+			for _, s := range services {
+				found[s.Type] = true
+			}
+			if !found[tt.want] || found[tt.absent] {
+				t.Errorf("Services %v, want %s without %s", services, tt.want, tt.absent)
+			}
+		}
+		os.Remove("build.gradle")
+	})
+
+	// Test 7: Legacy databases from the connection settings and the drivers
+	t.Run("Legacy databases from JDBC URLs and drivers", func(t *testing.T) {
+		propertiesContent := `
+orders.datasource.url=jdbc:mariadb://localhost:3306/orders
+billing.datasource.url=jdbc:sqlserver://localhost:1433;databaseName=billing
+legacy.datasource.url=jdbc:oracle:thin:@//localhost:1521/XEPDB1
+spring.neo4j.uri=bolt://localhost:7687
+`
+		gradleContent := `dependencies {
+    implementation 'com.oracle.database.jdbc:ojdbc11:23.3.0.23.09'
+    implementation 'org.springframework.boot:spring-boot-starter-data-cassandra'
+}`
+		if err := os.MkdirAll("src/main/resources", 0755); err != nil {
+			t.Fatalf("Unable to create directories: %v", err)
+		}
+		if err := os.WriteFile("src/main/resources/application.properties", []byte(propertiesContent), 0644); err != nil {
+			t.Fatalf("Error creating application.properties file: %v", err)
+		}
+		defer os.Remove("src/main/resources/application.properties")
+		if err := os.WriteFile("build.gradle", []byte(gradleContent), 0644); err != nil {
+			t.Fatalf("Error during build.gradle creation: %v", err)
+		}
+		defer os.Remove("build.gradle")
+
+		services, err := DetectServices()
+		if err != nil {
+			t.Errorf("Error during the services scan: %v", err)
+		}
+
+		found := make(map[ServiceType]int)
+		for _, s := range services {
+			found[s.Type]++
+		}
+		for _, serviceType := range []ServiceType{MariaDB, SQLServer, OracleXE, Neo4j, Cassandra} {
+			if found[serviceType] != 1 {
+				t.Errorf("%s Service detected %d times, want once: %v", serviceType, found[serviceType], services)
+			}
+		}
+		if found[Oracle] != 0 {
+			t.Errorf("Oracle Free should not be detected along Oracle XE: %v", services)
+		}
+	})
+
+	// Test 8: Spring Cloud infrastructure
+	t.Run("Spring Cloud Config and discovery", func(t *testing.T) {
+		pomContent := `<dependencies>
+    <dependency><artifactId>spring-cloud-starter-config</artifactId></dependency>
+    <dependency><artifactId>spring-cloud-starter-netflix-eureka-client</artifactId></dependency>
+    <dependency><artifactId>spring-cloud-starter-consul-discovery</artifactId></dependency>
+</dependencies>`
+		if err := os.WriteFile("pom.xml", []byte(pomContent), 0644); err != nil {
+			t.Fatalf("Error creating pom.xml file: %v", err)
+		}
+		defer os.Remove("pom.xml")
+		if err := os.MkdirAll("config-repo", 0755); err != nil {
+			t.Fatalf("Unable to create directories: %v", err)
+		}
+		defer os.RemoveAll("config-repo")
+		if err := os.WriteFile("config-repo/application.yml", []byte("greeting: hello\n"), 0644); err != nil {
+			t.Fatalf("Error creating config-repo/application.yml file: %v", err)
+		}
+
+		services, err := DetectServices()
+		if err != nil {
+			t.Errorf("Error during the services scan: %v", err)
+		}
+
+		found := make(map[ServiceType]ServiceConfig)
+		for _, s := range services {
+			found[s.Type] = s
+		}
+		for _, serviceType := range []ServiceType{ConfigServer, Eureka, Consul} {
+			if _, ok := found[serviceType]; !ok {
+				t.Errorf("%s Service not detected: %v", serviceType, services)
+			}
+		}
+		if dir := found[ConfigServer].Dir; dir != "." {
+			t.Errorf("Config Server directory = %q, want the project", dir)
+		}
+	})
+
+	// Test 9: No service
 	t.Run("No Services", func(t *testing.T) {
 		// Don't create any configuration files
 
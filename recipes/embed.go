@@ -0,0 +1,10 @@
+// Package recipes embeds the dependency recipes shipped with Turbotilt, so that an installed
+// binary knows the same dependent services as a source checkout.
+package recipes
+
+import "embed"
+
+// FS contains the shipped recipes, overridden by the user and project recipes
+//
+//go:embed *.yaml
+var FS embed.FS
@@ -0,0 +1,10 @@
+// Package templates embeds the templates shipped with Turbotilt, so that an installed
+// binary renders the same files as a source checkout.
+package templates
+
+import "embed"
+
+// FS contains the shipped templates, looked up after the project and user templates
+//
+//go:embed Tiltfile.tmpl Tiltfile.multi.tmpl
+var FS embed.FS